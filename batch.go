@@ -0,0 +1,330 @@
+package emailverifier
+
+import (
+	"context"
+	"iter"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	defaultBatchConcurrency       = 10
+	defaultBatchDomainConcurrency = 2
+
+	// idle domains are forgotten once a batch holds this many
+	maxBatchDomains = 10000
+	// ordered batches read at most this many addresses ahead of the next one to yield
+	maxBatchPending = 1000
+)
+
+// BatchOptions configures VerifyMany. The zero value is usable.
+type BatchOptions struct {
+	// Concurrency is the number of verifications running at once, 10 by default.
+	Concurrency int
+	// DomainConcurrency caps the verifications running at once against one domain,
+	// 2 by default.
+	DomainConcurrency int
+	// Rate caps the verifications started per second. No limit when 0.
+	Rate float64
+	// Ordered yields the results in input order rather than as they finish.
+	Ordered bool
+	// Progress is called after each result, from the goroutine ranging over the results.
+	Progress func(BatchProgress)
+}
+
+// BatchProgress counts the addresses read from the input so far and the results
+// finished, of which Failed have a Failure. Domains counts the domains looked up, a
+// domain forgotten while idle and seen again counts twice.
+type BatchProgress struct {
+	Read    int `json:"read"`
+	Done    int `json:"done"`
+	Failed  int `json:"failed"`
+	Domains int `json:"domains"`
+}
+
+// domainState holds what a batch learns once per domain and shares between its
+// addresses. A nil *domainState does every lookup afresh.
+type domainState struct {
+	sem   chan struct{}
+	users int
+
+	mxOnce sync.Once
+	mx     *Mx
+	mxErr  error
+
+	healthOnce sync.Once
+	health     *DomainHealth
+	healthErr  error
+
+	mu     sync.Mutex
+	probes []rcptProbe
+}
+
+func (d *domainState) checkMX(v *Verifier, domain string) (*Mx, error) {
+	if d == nil {
+		return v.CheckMX(domain)
+	}
+	d.mxOnce.Do(func() { d.mx, d.mxErr = v.CheckMX(domain) })
+	return d.mx, d.mxErr
+}
+
+// mxRecords returns the MX records checkMX found, and only looks them up for a nil d.
+func (d *domainState) mxRecords(ctx context.Context, v *Verifier, domain string) ([]*net.MX, error) {
+	if d == nil {
+		start := time.Now()
+		records, err := lookupMXRecords(ctx, domain)
+		v.observe(DNSLookupEvent{Type: "MX", Name: domain, Duration: time.Since(start), Err: err})
+		return records, err
+	}
+	mx, err := d.checkMX(v, domain)
+	if err != nil {
+		return nil, err
+	}
+	if len(mx.Records) == 0 {
+		return nil, errNoMXRecords
+	}
+	return mx.Records, nil
+}
+
+func (d *domainState) checkDomainHealth(v *Verifier, domain string) (*DomainHealth, error) {
+	if d == nil {
+		return v.CheckDomainHealth(domain)
+	}
+	d.healthOnce.Do(func() { d.health, d.healthErr = v.CheckDomainHealth(domain) })
+	return d.health, d.healthErr
+}
+
+// catchAllProbes returns the catch-all probes of an earlier address of the domain.
+func (d *domainState) catchAllProbes() ([]rcptProbe, bool) {
+	if d == nil {
+		return nil, false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.probes, d.probes != nil
+}
+
+// setCatchAllProbes keeps probes for the next addresses when at least one of them got
+// a definite answer.
+func (d *domainState) setCatchAllProbes(probes []rcptProbe) {
+	if d == nil {
+		return
+	}
+	if accepted, rejected := countProbes(probes); accepted+rejected == 0 {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.probes = probes
+}
+
+// batchDomains hands out the domainState of each domain to the workers of a batch.
+type batchDomains struct {
+	mu          sync.Mutex
+	states      map[string]*domainState
+	concurrency int
+	seen        int
+}
+
+func (b *batchDomains) acquire(domain string) *domainState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d, ok := b.states[domain]
+	if !ok {
+		if len(b.states) >= maxBatchDomains {
+			b.evictIdle()
+		}
+		d = &domainState{sem: make(chan struct{}, b.concurrency)}
+		b.states[domain] = d
+		b.seen++
+	}
+	d.users++
+	return d
+}
+
+func (b *batchDomains) release(d *domainState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	d.users--
+}
+
+// evictIdle forgets the domains no verification is running for, to bound long batches.
+func (b *batchDomains) evictIdle() {
+	for domain, d := range b.states {
+		if d.users == 0 {
+			delete(b.states, domain)
+		}
+	}
+}
+
+func (b *batchDomains) count() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.seen
+}
+
+type batchResult struct {
+	index int
+	res   *Result
+}
+
+// VerifyMany verifies the addresses read from emails and yields each result with the
+// position of its address in the input. MX records, domain health and catch-all probes
+// are looked up once per domain, and addresses of a domain known to be catch-all are not
+// connected for at all. Stopping the iteration, or canceling ctx, stops reading input and
+// aborts the running verifications; the iteration only ends once emails is no longer
+// being read. Errors are reported in Result.Failure.
+func (v *Verifier) VerifyMany(ctx context.Context, emails iter.Seq[string], opts *BatchOptions) iter.Seq2[int, *Result] {
+	if opts == nil {
+		opts = &BatchOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBatchConcurrency
+	}
+	domainConcurrency := opts.DomainConcurrency
+	if domainConcurrency <= 0 {
+		domainConcurrency = defaultBatchDomainConcurrency
+	}
+
+	return func(yield func(int, *Result) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		// wg covers the goroutine reading emails and the workers, none of them may
+		// outlive the iteration
+		var wg sync.WaitGroup
+		defer func() {
+			cancel()
+			wg.Wait()
+		}()
+
+		var limiter <-chan time.Time
+		if opts.Rate > 0 {
+			ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+			defer ticker.Stop()
+			limiter = ticker.C
+		}
+
+		domains := &batchDomains{states: map[string]*domainState{}, concurrency: domainConcurrency}
+
+		// in order, a result waits for the ones before it; window keeps the addresses
+		// read ahead of the next one to yield bounded
+		var window chan struct{}
+		if opts.Ordered {
+			window = make(chan struct{}, max(maxBatchPending, concurrency))
+		}
+
+		type item struct {
+			index int
+			email string
+		}
+		var read atomic.Int64
+		items := make(chan item)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(items)
+			i := 0
+			for email := range emails {
+				if window != nil {
+					select {
+					case window <- struct{}{}:
+					case <-ctx.Done():
+						return
+					}
+				}
+				select {
+				case items <- item{i, email}:
+				case <-ctx.Done():
+					return
+				}
+				i++
+				read.Add(1)
+			}
+		}()
+
+		results := make(chan batchResult, concurrency)
+		for range concurrency {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for it := range items {
+					res, ok := v.verifyInBatch(ctx, it.email, limiter, domains)
+					if !ok {
+						return
+					}
+					select {
+					case results <- batchResult{it.index, res}:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}()
+		}
+		go func() {
+			wg.Wait()
+			close(results)
+		}()
+
+		var progress BatchProgress
+		next := 0
+		pending := map[int]*Result{}
+		for r := range results {
+			progress.Done++
+			if r.res.Failure != nil {
+				progress.Failed++
+			}
+			if opts.Progress != nil {
+				progress.Domains = domains.count()
+				progress.Read = int(read.Load())
+				opts.Progress(progress)
+			}
+
+			if !opts.Ordered {
+				if !yield(r.index, r.res) {
+					return
+				}
+				continue
+			}
+			pending[r.index] = r.res
+			for res, ok := pending[next]; ok; res, ok = pending[next] {
+				delete(pending, next)
+				<-window
+				if !yield(next, res) {
+					return
+				}
+				next++
+			}
+		}
+	}
+}
+
+// verifyInBatch waits for the rate limiter and a free slot for the domain, then
+// verifies email. It reports false when ctx was canceled while waiting.
+func (v *Verifier) verifyInBatch(ctx context.Context, email string, limiter <-chan time.Time, domains *batchDomains) (*Result, bool) {
+	syntax := v.ParseAddress(email)
+	if !syntax.Valid {
+		res, _ := v.verifyWith(ctx, email, nil)
+		return res, true
+	}
+
+	if limiter != nil {
+		select {
+		case <-limiter:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+	d := domains.acquire(syntax.Domain)
+	defer domains.release(d)
+	select {
+	case d.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, false
+	}
+	defer func() { <-d.sem }()
+
+	res, _ := v.verifyWith(ctx, email, d)
+	return res, true
+}
@@ -0,0 +1,182 @@
+package emailverifier
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+)
+
+const (
+	catchAllMinProbes     = 2
+	defaultCatchAllProbes = 4
+
+	// domains are catch-all above this confidence, probes without any definite answer
+	// leave it at this value
+	catchAllThreshold = 0.5
+
+	// accepting unknown recipients this much slower than the real one hints at a
+	// server that does look recipients up
+	catchAllTimingRatio = 3
+	catchAllTimingSlack = 500 * time.Millisecond
+)
+
+var (
+	probeGivenNames = []string{
+		"james", "mary", "john", "linda", "michael", "sarah", "david", "laura", "daniel", "emma",
+		"thomas", "anna", "robert", "julia", "mark", "sophie", "paul", "claire", "peter", "nina",
+		"lukas", "lea", "jonas", "marie", "pierre", "camille", "marco", "giulia", "carlos", "lucia",
+		"jan", "eva", "erik", "ingrid", "ahmed", "fatima", "kenji", "yuki", "raj", "priya",
+	}
+	probeSurnames = []string{
+		"smith", "johnson", "brown", "taylor", "wilson", "evans", "walker", "wright", "green", "hall",
+		"baker", "carter", "mitchell", "turner", "parker", "collins", "morgan", "cooper", "reed", "bell",
+		"muller", "schmidt", "weber", "wagner", "martin", "bernard", "dubois", "rossi", "bianchi", "garcia",
+		"lopez", "martinez", "jansen", "devries", "nielsen", "larsen", "novak", "kowalski", "tanaka", "sharma",
+	}
+)
+
+// rcptProbe is the outcome of a single RCPT command.
+type rcptProbe struct {
+	accepted bool
+	code     int
+	duration time.Duration
+	err      *LookupError
+}
+
+// rejected reports a permanent rejection of the mailbox itself.
+func (p rcptProbe) rejected() bool {
+	if p.err == nil || p.code < 500 {
+		return false
+	}
+	switch p.err.Code {
+	case ErrCodeServerUnavailable, ErrCodeRCPTHasMoved, ErrCodeNoRelay:
+		return true
+	}
+	return false
+}
+
+// CatchAllProbes sets how many random recipients may be probed when earlier probes are
+// ambiguous, at least two.
+func (v *Verifier) CatchAllProbes(max int) *Verifier {
+	v.catchAllProbes = max
+	return v
+}
+
+func (c *smtpConn) probeRcpt(addr string) rcptProbe {
+	start := time.Now()
+	err := c.rcpt(addr)
+	p := rcptProbe{accepted: err == nil, code: smtpReplyCode(err), duration: time.Since(start)}
+	if err != nil {
+		p.err = parseErrorAt(err, StageRCPT)
+	}
+	return p
+}
+
+// probeCatchAll sends RCPTs for random realistic addresses until the answers agree, and
+// keeps probing up to maxProbes while they are ambiguous or temporary.
+func probeCatchAll(client *smtpConn, domain string, maxProbes int) []rcptProbe {
+	maxProbes = max(maxProbes, catchAllMinProbes)
+	probes := make([]rcptProbe, 0, maxProbes)
+	for _, addr := range GenerateSmartRandomEmails(domain, maxProbes) {
+		p := client.probeRcpt(addr)
+		probes = append(probes, p)
+		// without a reply code the connection is gone
+		if p.err != nil && p.code == 0 {
+			break
+		}
+		if p.err != nil && p.err.Code == ErrCodeBlocked {
+			break
+		}
+		if len(probes) >= catchAllMinProbes && catchAllDecisive(probes) {
+			break
+		}
+	}
+	return probes
+}
+
+// catchAllDecisive reports whether enough probes got a definitive answer and all of
+// them agree.
+func catchAllDecisive(probes []rcptProbe) bool {
+	accepted, rejected := countProbes(probes)
+	if accepted+rejected < catchAllMinProbes || accepted+rejected < len(probes) {
+		return false
+	}
+	return accepted == 0 || rejected == 0
+}
+
+func countProbes(probes []rcptProbe) (accepted, rejected int) {
+	for _, p := range probes {
+		switch {
+		case p.accepted:
+			accepted++
+		case p.rejected():
+			rejected++
+		}
+	}
+	return accepted, rejected
+}
+
+// catchAllConfidence estimates how likely the domain accepts any recipient, from the
+// share of accepted probes, adjusted by how the real recipient compares to them. real
+// is nil when no real recipient was probed.
+func catchAllConfidence(probes []rcptProbe, real *rcptProbe) float64 {
+	accepted, rejected := countProbes(probes)
+	if accepted+rejected == 0 {
+		return catchAllThreshold
+	}
+	confidence := float64(accepted) / float64(accepted+rejected)
+
+	if real != nil && accepted > 0 {
+		var total time.Duration
+		codes := map[int]bool{}
+		for _, p := range probes {
+			if p.accepted {
+				total += p.duration
+				codes[p.code] = true
+			}
+		}
+		mean := total / time.Duration(accepted)
+		switch {
+		case real.accepted:
+			if mean > catchAllTimingRatio*real.duration && mean-real.duration > catchAllTimingSlack {
+				confidence *= 0.5
+			}
+			if !codes[real.code] {
+				confidence *= 0.75
+			}
+		case real.rejected():
+			// the server does tell mailboxes apart, at least this one
+			confidence *= 0.5
+		}
+	}
+	return math.Round(confidence*100) / 100
+}
+
+// isCatchAll is false for an unknown confidence, accepting the real recipient then
+// still counts.
+func isCatchAll(confidence float64) bool {
+	return confidence > catchAllThreshold
+}
+
+// randomLocalPart builds a local part looking like a real person's address, so probes
+// are not singled out by filters looking for verification traffic. Each one carries a
+// random number, plain names would hit real mailboxes at larger domains.
+func randomLocalPart(r *rand.Rand) string {
+	first := probeGivenNames[r.Intn(len(probeGivenNames))]
+	last := probeSurnames[r.Intn(len(probeSurnames))]
+	switch r.Intn(6) {
+	case 0:
+		return fmt.Sprintf("%s.%s%d", first, last, r.Intn(900)+100)
+	case 1:
+		return fmt.Sprintf("%s%s%d", first, last, r.Intn(40)+1960)
+	case 2:
+		return fmt.Sprintf("%s.%s%d", first[:1], last, r.Intn(900)+100)
+	case 3:
+		return fmt.Sprintf("%s_%s%d", first, last, r.Intn(90)+10)
+	case 4:
+		return fmt.Sprintf("%s.%c.%s%d", first, 'a'+r.Intn(26), last, r.Intn(90)+10)
+	default:
+		return fmt.Sprintf("%s%s%d", first, last[:1], r.Intn(900)+100)
+	}
+}
@@ -3,13 +3,17 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"compress/gzip"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"os"
 	"os/exec"
-	"strconv"
-)
+	"path/filepath"
+	"sort"
+	"time"
+)
 func writeFile(filePath string, data []byte) {
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		log.Fatalf("no such file: %s make sure running from the root of the repo directory", filePath)
@@ -26,73 +30,125 @@ type fileInfo struct {
 	path        string
 	varName     string
 	srcPath     string
+	dataPath    string
 	description string
+	domains     bool
 }
 
-func buildMetaDataFile() {
-	var files []fileInfo
-	files = append(files,
-		fileInfo{
-			path:        "disposable.txt",
-			varName:     "disposableDomains",
-			srcPath:     "../../metadata_disposable.go",
-			description: "// map to store disposable domains data",
-		},
-		fileInfo{
-			path:        "free.txt",
-			varName:     "freeDomains",
-			srcPath:     "../../metadata_free.go",
-			description: "// map to store free domains data",
-		},
-		fileInfo{
-			path:        "role.txt",
-			varName:     "roleAccounts",
-			srcPath:     "../../metadata_role.go",
-			description: "// map to store role-based accounts data",
-		},
-	)
-
-	for _, f := range files {
-		log.Printf("Building map for: %s\n", f.path)
-		file, err := os.Open(f.path)
-		if err != nil {
-			panic(fmt.Sprintf("open meta data f %s fail: %v ", f, err))
-		}
-
-		output := bytes.Buffer{}
-		output.WriteString("// Code generated by cmd/build_metadata; DO NOT EDIT.\n\n")
-		output.WriteString("package emailverifier\n\n")
-		output.WriteString(f.description + "\n")
-		output.WriteString(fmt.Sprintf("var %s = map[string]bool {\n", f.varName))
+type metaDataList struct {
+	info       fileInfo
+	keys       []string
+	duplicates []string
+}
 
-		scanner := bufio.NewScanner(file)
-		scanner.Split(bufio.ScanLines)
+var metaDataFiles = []fileInfo{
+	{
+		path:        "disposable.txt",
+		varName:     "disposableDomains",
+		srcPath:     "../../metadata_disposable.go",
+		dataPath:    "metadata/disposable.txt.gz",
+		description: "// list to store disposable domains data",
+		domains:     true,
+	},
+	{
+		path:        "free.txt",
+		varName:     "freeDomains",
+		srcPath:     "../../metadata_free.go",
+		dataPath:    "metadata/free.txt.gz",
+		description: "// list to store free domains data",
+		domains:     true,
+	},
+	{
+		path:        "role.txt",
+		varName:     "roleAccounts",
+		srcPath:     "../../metadata_role.go",
+		dataPath:    "metadata/role.txt.gz",
+		description: "// list to store role-based accounts data",
+	},
+	{
+		path:        "given_names.txt",
+		varName:     "givenNames",
+		srcPath:     "../../metadata_given_names.go",
+		dataPath:    "metadata/given_names.txt.gz",
+		description: "// list to store given names used to infer names from usernames",
+	},
+	{
+		path:        "surnames.txt",
+		varName:     "surnames",
+		srcPath:     "../../metadata_surnames.go",
+		dataPath:    "metadata/surnames.txt.gz",
+		description: "// list to store surnames used to infer names from usernames",
+	},
+}
 
-		data := make(map[string]bool)
-		for scanner.Scan() {
-			key := scanner.Text()
+func readMetaDataFile(f fileInfo) metaDataList {
+	log.Printf("Building list for: %s\n", f.path)
+	file, err := os.Open(f.path)
+	if err != nil {
+		panic(fmt.Sprintf("open meta data f %s fail: %v ", f.path, err))
+	}
 
-			if !data[key] {
-				output.WriteString("\t")
-				output.WriteString(strconv.Quote(key))
-				output.WriteString(": ")
-				output.WriteString("true")
-				output.WriteString(",\n")
+	scanner := bufio.NewScanner(file)
+	scanner.Split(bufio.ScanLines)
 
-			}
-			data[key] = true
+	list := metaDataList{info: f}
+	data := make(map[string]bool)
+	for scanner.Scan() {
+		key := scanner.Text()
+		if key == "" {
+			continue
 		}
-		output.WriteString("}")
-		log.Printf("Read %d mappings in %s\n", len(data), f.path)
-
-		err = file.Close()
-		if err != nil {
-			panic(fmt.Sprintf("close role meta data file %s fail: %v ", f.path, err))
+		if data[key] {
+			list.duplicates = append(list.duplicates, key)
+			continue
 		}
-		writeFile(f.srcPath, output.Bytes())
+		data[key] = true
+		list.keys = append(list.keys, key)
+	}
+	sort.Strings(list.keys)
+	log.Printf("Read %d entries in %s\n", len(list.keys), f.path)
+
+	err = file.Close()
+	if err != nil {
+		panic(fmt.Sprintf("close role meta data file %s fail: %v ", f.path, err))
+	}
+	return list
+}
 
+func writeMetaDataFile(list metaDataList) {
+	writeFile(filepath.Join("../..", list.info.dataPath), compressList(list.keys))
+	writeFile(list.info.srcPath, generateSource(list.info))
+}
+
+func compressList(keys []string) []byte {
+	output := bytes.Buffer{}
+	zw, err := gzip.NewWriterLevel(&output, gzip.BestCompression)
+	if err != nil {
+		panic(fmt.Sprintf("create gzip writer fail: %v", err))
+	}
+	// fixed header so regenerating unchanged lists produces identical files
+	zw.ModTime = time.Time{}
+	for _, key := range keys {
+		_, _ = zw.Write([]byte(key))
+		_, _ = zw.Write([]byte{'\n'})
+	}
+	if err = zw.Close(); err != nil {
+		panic(fmt.Sprintf("compress meta data fail: %v", err))
 	}
+	return output.Bytes()
+}
 
+func generateSource(f fileInfo) []byte {
+	output := bytes.Buffer{}
+	output.WriteString("// Code generated by cmd/build_metadata; DO NOT EDIT.\n\n")
+	output.WriteString("package emailverifier\n\n")
+	output.WriteString("import _ \"embed\"\n\n")
+	output.WriteString(f.description + "\n")
+	output.WriteString("//\n")
+	output.WriteString(fmt.Sprintf("//go:embed %s\n", f.dataPath))
+	output.WriteString(fmt.Sprintf("var %sData []byte\n\n", f.varName))
+	output.WriteString(fmt.Sprintf("var %s = newMetadataList(%sData)\n", f.varName, f.varName))
+	return output.Bytes()
 }
 
 func updateMetaData() {
@@ -136,6 +192,38 @@ func updateMetaData() {
 }
 
 func main() {
-	updateMetaData()
-	buildMetaDataFile()
+	offline := flag.Bool("offline", false, "build from the local list files without running update.sh")
+	check := flag.Bool("check", false, "validate and report changes without writing any files")
+	dropInvalid := flag.Bool("drop-invalid", false, "drop invalid entries instead of failing the build")
+	reportLimit := flag.Int("report-limit", 100, "max added/removed entries printed per list, 0 for no limit")
+	flag.Parse()
+
+	if !*offline {
+		updateMetaData()
+	}
+
+	lists := make([]metaDataList, 0, len(metaDataFiles))
+	for _, f := range metaDataFiles {
+		lists = append(lists, readMetaDataFile(f))
+	}
+
+	problems := validateMetaData(lists)
+	printProblems(problems)
+	if len(problems) > 0 {
+		if !*dropInvalid {
+			log.Fatalf("%d invalid meta data entries, fix the lists or run with -drop-invalid", len(problems))
+		}
+		lists = dropProblems(lists, problems)
+	}
+
+	for _, list := range lists {
+		printChangeReport(list, *reportLimit)
+	}
+
+	if *check {
+		return
+	}
+	for _, list := range lists {
+		writeMetaDataFile(list)
+	}
 }
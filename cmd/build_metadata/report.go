@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+func readPreviousList(f fileInfo) []string {
+	file, err := os.Open(filepath.Join("../..", f.dataPath))
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+
+	zr, err := gzip.NewReader(file)
+	if err != nil {
+		return nil
+	}
+	var keys []string
+	scanner := bufio.NewScanner(zr)
+	for scanner.Scan() {
+		if key := scanner.Text(); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// diffLists returns the entries only in next (added) and only in prev (removed).
+// Both slices must be sorted.
+func diffLists(prev, next []string) (added, removed []string) {
+	i, j := 0, 0
+	for i < len(prev) || j < len(next) {
+		switch {
+		case j == len(next) || (i < len(prev) && prev[i] < next[j]):
+			removed = append(removed, prev[i])
+			i++
+		case i == len(prev) || next[j] < prev[i]:
+			added = append(added, next[j])
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return added, removed
+}
+
+func printChangeReport(list metaDataList, limit int) {
+	prev := readPreviousList(list.info)
+	added, removed := diffLists(prev, list.keys)
+	fmt.Printf("%s: %d entries (was %d), %d added, %d removed\n",
+		list.info.path, len(list.keys), len(prev), len(added), len(removed))
+	printEntries("+", added, limit)
+	printEntries("-", removed, limit)
+}
+
+func printEntries(prefix string, entries []string, limit int) {
+	for i, e := range entries {
+		if limit > 0 && i == limit {
+			fmt.Printf("  ... %d more\n", len(entries)-limit)
+			return
+		}
+		fmt.Printf("  %s %s\n", prefix, e)
+	}
+}
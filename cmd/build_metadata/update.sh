@@ -18,7 +18,7 @@ tmp=$(mktemp -t emailverifierXXX)
 cat $new ./free.txt \
     | sed '/^$/d' \
     | sed '/./,$!d' \
-    | sed -e 's/^ *//' -e 's/ *$//' \
+    | sed -e 's/\xc2\xa0//g' -e 's/^ *//' -e 's/ *$//' \
     | awk '{print tolower($0)}' \
     | sort \
     | uniq \
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+)
+
+type problem struct {
+	path   string
+	entry  string
+	reason string
+}
+
+// validateMetaData normalizes every entry in place and returns the entries that cannot be
+// used: invalid domains or usernames, and domains listed in more than one domain list.
+func validateMetaData(lists []metaDataList) []problem {
+	var problems []problem
+	seen := make(map[string]string)
+
+	for i := range lists {
+		list := &lists[i]
+		for _, d := range list.duplicates {
+			log.Printf("%s: duplicate entry %q removed\n", list.info.path, d)
+		}
+
+		keys := make([]string, 0, len(list.keys))
+		for _, key := range list.keys {
+			normalized, err := normalizeEntry(key, list.info.domains)
+			if err != nil {
+				problems = append(problems, problem{list.info.path, key, err.Error()})
+				continue
+			}
+			if normalized != key {
+				log.Printf("%s: entry %q normalized to %q\n", list.info.path, key, normalized)
+			}
+			keys = append(keys, normalized)
+		}
+		list.keys = uniqueSorted(keys)
+
+		if !list.info.domains {
+			continue
+		}
+		for _, key := range list.keys {
+			if other, found := seen[key]; found {
+				problems = append(problems, problem{list.info.path, key, "already listed in " + other})
+				continue
+			}
+			seen[key] = list.info.path
+		}
+	}
+	return problems
+}
+
+func normalizeEntry(entry string, domain bool) (string, error) {
+	entry = strings.ToLower(strings.TrimSpace(entry))
+	if !domain {
+		if strings.ContainsFunc(entry, func(r rune) bool { return r == '@' || unicode.IsSpace(r) || !unicode.IsPrint(r) }) {
+			return "", fmt.Errorf("invalid username")
+		}
+		return entry, nil
+	}
+	ascii, err := idna.Lookup.ToASCII(entry)
+	if err != nil {
+		return "", fmt.Errorf("invalid domain: %v", err)
+	}
+	if !strings.Contains(ascii, ".") {
+		return "", fmt.Errorf("invalid domain: missing top level domain")
+	}
+	return ascii, nil
+}
+
+func uniqueSorted(keys []string) []string {
+	sort.Strings(keys)
+	out := keys[:0]
+	for i, k := range keys {
+		if i > 0 && k == keys[i-1] {
+			continue
+		}
+		out = append(out, k)
+	}
+	return out
+}
+
+func printProblems(problems []problem) {
+	for _, p := range problems {
+		fmt.Printf("INVALID %s: %q %s\n", p.path, p.entry, p.reason)
+	}
+}
+
+func dropProblems(lists []metaDataList, problems []problem) []metaDataList {
+	drop := make(map[string]map[string]bool)
+	for _, p := range problems {
+		if drop[p.path] == nil {
+			drop[p.path] = make(map[string]bool)
+		}
+		drop[p.path][p.entry] = true
+	}
+	for i := range lists {
+		keys := lists[i].keys[:0]
+		for _, key := range lists[i].keys {
+			if !drop[lists[i].info.path][key] {
+				keys = append(keys, key)
+			}
+		}
+		lists[i].keys = keys
+	}
+	return lists
+}
@@ -5,6 +5,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	emailverifier "cleanmails"
 )
 
 type Config struct {
@@ -20,12 +22,33 @@ type Config struct {
 	ValidationRate        float64
 	RateJitter            float64
 	LocalIPs              []string
+	IPMaxBlocks           int
+	IPQuarantine          time.Duration
+	Proxies               []string
+	ProxyStrategy         string
+	ProxySticky           bool
 
 	SMTPConnectTimeout   time.Duration
 	SMTPOperationTimeout time.Duration
+	SMTPPort             int
 	SMTPFromEmail        string
+	SMTPFromOverrides    []string
 	SMTPHelloName        string
+	SMTPHelloAuto        bool
 	SMTPCatchAll         bool
+	SMTPStartTLS         bool
+	SMTPVRFY             bool
+	SMTPEXPN             bool
+	SMTPAddressFamily    string
+
+	DNSBLZones []string
+
+	HTTPUserAgent string
+	HTTPTimeout   time.Duration
+
+	InferNames bool
+
+	LogVerifierEvents bool
 }
 
 func LoadConfig() Config {
@@ -42,12 +65,33 @@ func LoadConfig() Config {
 		ValidationRate:        getEnvFloat("VALIDATION_RATE", 20.0),
 		RateJitter:            getEnvFloat("RATE_JITTER", 0.1),
 		LocalIPs:              getEnvStringSlice("LOCAL_IPS", []string{}),
+		IPMaxBlocks:           getEnvInt("IP_MAX_BLOCKS", 3),
+		IPQuarantine:          getEnvDuration("IP_QUARANTINE", 30*time.Minute),
+		Proxies:               getEnvStringSlice("PROXIES", []string{}),
+		ProxyStrategy:         getEnvString("PROXY_STRATEGY", emailverifier.ProxySelectRoundRobin),
+		ProxySticky:           getEnvBool("PROXY_STICKY", false),
 
 		SMTPConnectTimeout:   getEnvDuration("SMTP_CONNECT_TIMEOUT", 10*time.Second),
 		SMTPOperationTimeout: getEnvDuration("SMTP_OPERATION_TIMEOUT", 10*time.Second),
+		SMTPPort:             getEnvInt("SMTP_PORT", 25),
 		SMTPFromEmail:        getEnvString("SMTP_FROM_EMAIL", "user@example.org"),
+		SMTPFromOverrides:    getEnvStringSlice("SMTP_FROM_OVERRIDES", []string{}),
 		SMTPHelloName:        getEnvString("SMTP_HELO_NAME", "localhost"),
+		SMTPHelloAuto:        getEnvBool("SMTP_HELO_AUTO", false),
 		SMTPCatchAll:         getEnvBool("SMTP_CATCH_ALL", true),
+		SMTPStartTLS:         getEnvBool("SMTP_STARTTLS", false),
+		SMTPVRFY:             getEnvBool("SMTP_VRFY", false),
+		SMTPEXPN:             getEnvBool("SMTP_EXPN", false),
+		SMTPAddressFamily:    getEnvString("SMTP_ADDRESS_FAMILY", emailverifier.FamilyHappyEyeballs),
+
+		DNSBLZones: getEnvStringSlice("DNSBL_ZONES", emailverifier.DefaultDNSBLZones),
+
+		HTTPUserAgent: getEnvString("HTTP_USER_AGENT", ""),
+		HTTPTimeout:   getEnvDuration("HTTP_TIMEOUT", 10*time.Second),
+
+		InferNames: getEnvBool("INFER_NAMES", false),
+
+		LogVerifierEvents: getEnvBool("LOG_VERIFIER_EVENTS", false),
 	}
 }
 
@@ -9,7 +9,6 @@ import (
 	"log"
 	"math"
 	"math/rand"
-	"net"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -17,7 +16,6 @@ import (
 	"strconv"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	emailverifier "cleanmails"
@@ -31,8 +29,11 @@ type Server struct {
 	level1Sem chan struct{}
 	level2Sem chan struct{}
 	rateCh    chan struct{}
-	reqCount  uint64
 	mxCache   sync.Map // cache for *emailverifier.Mx
+	proxyPool *emailverifier.ProxyPool
+	ipPool    *emailverifier.IPPool
+	metrics   *emailverifier.MetricsCollector
+	observer  emailverifier.Observer
 }
 
 type VerifyRequest struct {
@@ -40,6 +41,12 @@ type VerifyRequest struct {
 	Level int    `json:"level"`
 }
 
+type FindRequest struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Domain    string `json:"domain"`
+}
+
 type BulkRequest struct {
 	Emails        []string `json:"emails"`
 	Level         int      `json:"level"`
@@ -74,6 +81,25 @@ func NewServer(cfg Config) *Server {
 		level1Sem: make(chan struct{}, cfg.Level1Concurrency),
 		level2Sem: make(chan struct{}, cfg.Level2Concurrency),
 		rateCh:    make(chan struct{}, 1000),
+		metrics:   emailverifier.NewMetricsCollector(),
+	}
+	s.observer = s.metrics
+	if cfg.LogVerifierEvents {
+		s.observer = emailverifier.MultiObserver(s.metrics, emailverifier.NewSlogObserver(nil))
+	}
+	if len(cfg.Proxies) > 0 {
+		pool, err := emailverifier.NewProxyPool(cfg.Proxies)
+		if err != nil {
+			log.Fatalf("[Init] invalid PROXIES: %v", err)
+		}
+		s.proxyPool = pool.Strategy(cfg.ProxyStrategy).StickyDomains(cfg.ProxySticky)
+	}
+	if len(cfg.LocalIPs) > 0 {
+		pool, err := emailverifier.NewIPPool(cfg.LocalIPs)
+		if err != nil {
+			log.Fatalf("[Init] invalid LOCAL_IPS: %v", err)
+		}
+		s.ipPool = pool.QuarantineAfter(cfg.IPMaxBlocks, cfg.IPQuarantine)
 	}
 	go s.startRateLimiter()
 	return s
@@ -109,8 +135,13 @@ func (s *Server) routes() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", s.handleHealth)
 	mux.HandleFunc("/v1/verify", s.handleVerify)
+	mux.HandleFunc("/v1/find", s.handleFind)
 	mux.HandleFunc("/v1/bulk", s.handleBulk)
 	mux.HandleFunc("/v1/bulk/", s.handleBulkByID)
+	mux.HandleFunc("/v1/proxies", s.handleProxies)
+	mux.HandleFunc("/v1/ips", s.handleIPs)
+	mux.HandleFunc("/v1/diagnostics", s.handleDiagnostics)
+	mux.Handle("/v1/metrics", s.metrics)
 
 	// Auth routes
 	mux.HandleFunc("/v1/auth/setup", s.handleAuthSetup)
@@ -147,34 +178,47 @@ func (s *Server) routes() http.Handler {
 	return s.auth.AuthMiddleware(mux)
 }
 
-func (s *Server) getNextLocalIP() string {
-	if len(s.cfg.LocalIPs) == 0 {
-		return ""
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
 	}
-	idx := atomic.AddUint64(&s.reqCount, 1) % uint64(len(s.cfg.LocalIPs))
-	return s.cfg.LocalIPs[idx]
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
 }
 
-func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+func (s *Server) handleProxies(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
 		return
 	}
-	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	stats := []emailverifier.ProxyStats{}
+	if s.proxyPool != nil {
+		stats = s.proxyPool.Stats()
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"proxies": stats})
 }
 
-func (s *Server) handleNetworkCheck(w http.ResponseWriter, r *http.Request) {
-	// Try to dial a common SMTP server on port 25 to check if host blocks it
-	conn, err := net.DialTimeout("tcp", "gmail-smtp-in.l.google.com:25", 3*time.Second)
-	if err != nil {
-		writeJSON(w, http.StatusOK, map[string]interface{}{
-			"port25": false,
-			"error":  err.Error(),
-		})
+func (s *Server) handleIPs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	stats := []emailverifier.IPStats{}
+	if s.ipPool != nil {
+		stats = s.ipPool.Stats()
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"ips": stats})
+}
+
+func (s *Server) handleDiagnostics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
 		return
 	}
-	conn.Close()
-	writeJSON(w, http.StatusOK, map[string]interface{}{"port25": true})
+	report := s.newVerifier(2).DiagnoseSender(&emailverifier.DiagnosticsOptions{
+		DNSBLZones: s.cfg.DNSBLZones,
+	})
+	writeJSON(w, http.StatusOK, report)
 }
 
 func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
@@ -206,6 +250,25 @@ func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, http.StatusOK, res)
 }
 
+func (s *Server) handleFind(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
+		return
+	}
+	var req FindRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_json")
+		return
+	}
+
+	res, err := s.newVerifier(2).FindEmail(req.FirstName, req.LastName, req.Domain)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
 func (s *Server) handleBulk(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		writeError(w, http.StatusMethodNotAllowed, "method_not_allowed")
@@ -396,24 +459,24 @@ func (s *Server) runJob(job *Job, emails []string, concurrency int, callbackURL
 			} else {
 				vJob := s.newVerifier(2)
 				res, err = vJob.Verify(email)
-				if err == nil && res.SMTP != nil && res.SMTP.CatchAll {
+				if err == nil && res.Failure == nil && res.SMTP != nil && res.SMTP.CatchAll {
 					job.setCatchAll(syntax.Domain, true)
 				}
 			}
 
-			if err != nil {
+			result := EmailResult{Email: email, Result: res}
+			switch {
+			case err != nil:
+				result.Error = err.Error()
+			case res.Failure != nil:
+				result.Error = res.Failure.Message
+			}
+			if result.Error != "" {
 				inc(&job.Failed)
-				result := EmailResult{Email: email, Error: err.Error()}
-				job.addResult(result)
-				if callback != nil {
-					callback.Enqueue(result)
-				}
-			} else {
-				result := EmailResult{Email: email, Result: res}
-				job.addResult(result)
-				if callback != nil {
-					callback.Enqueue(result)
-				}
+			}
+			job.addResult(result)
+			if callback != nil {
+				callback.Enqueue(result)
 			}
 			inc(&job.Done)
 		}
@@ -440,15 +503,46 @@ func (s *Server) newVerifier(level int) *emailverifier.Verifier {
 	verifier := emailverifier.NewVerifier().
 		ConnectTimeout(s.cfg.SMTPConnectTimeout).
 		OperationTimeout(s.cfg.SMTPOperationTimeout).
+		SMTPPort(s.cfg.SMTPPort).
 		FromEmail(s.cfg.SMTPFromEmail).
-		HelloName(s.cfg.SMTPHelloName)
+		HelloName(s.cfg.SMTPHelloName).
+		AddressFamily(s.cfg.SMTPAddressFamily).
+		UserAgent(s.cfg.HTTPUserAgent).
+		HTTPTimeout(s.cfg.HTTPTimeout).
+		Observer(s.observer)
+	if s.cfg.InferNames {
+		verifier.EnableNameInference()
+	}
+	if s.cfg.SMTPHelloAuto {
+		verifier.EnableAutoHelloName()
+	}
+	// overrides are given as domain=address, e.g. example.com=<>
+	for _, override := range s.cfg.SMTPFromOverrides {
+		if domain, from, ok := strings.Cut(override, "="); ok {
+			verifier.FromEmailFor(strings.TrimSpace(domain), strings.TrimSpace(from))
+		}
+	}
 
 	if level == 2 {
-		verifier.LocalAddr(s.getNextLocalIP())
+		if s.ipPool != nil {
+			verifier.IPPool(s.ipPool)
+		}
+		if s.proxyPool != nil {
+			verifier.ProxyPool(s.proxyPool)
+		}
 		verifier.EnableSMTPCheck()
 		if !s.cfg.SMTPCatchAll {
 			verifier.DisableCatchAllCheck()
 		}
+		if s.cfg.SMTPStartTLS {
+			verifier.EnableSTARTTLS()
+		}
+		if s.cfg.SMTPVRFY {
+			verifier.EnableVRFY()
+		}
+		if s.cfg.SMTPEXPN {
+			verifier.EnableEXPN()
+		}
 	}
 
 	return verifier
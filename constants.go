@@ -1,11 +1,16 @@
 package emailverifier
 
+import "time"
+
 const (
 	emailRegexString = "^(?:(?:(?:(?:[a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+(?:\\.([a-zA-Z]|\\d|[!#\\$%&'\\*\\+\\-\\/=\\?\\^_`{\\|}~]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])+)*)|(?:(?:\\x22)(?:(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(?:\\x20|\\x09)+)?(?:(?:[\\x01-\\x08\\x0b\\x0c\\x0e-\\x1f\\x7f]|\\x21|[\\x23-\\x5b]|[\\x5d-\\x7e]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[\\x01-\\x09\\x0b\\x0c\\x0d-\\x7f]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}]))))*(?:(?:(?:\\x20|\\x09)*(?:\\x0d\\x0a))?(\\x20|\\x09)+)?(?:\\x22))))@(?:(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|\\d|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.)+(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])|(?:(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])(?:[a-zA-Z]|\\d|-|\\.|~|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])*(?:[a-zA-Z]|[\\x{00A0}-\\x{D7FF}\\x{F900}-\\x{FDCF}\\x{FDF0}-\\x{FFEF}])))\\.?$"
 	defaultFromEmail = "user@example.org"
 	defaultHelloName = "localhost"
 
-	smtpPort = ":25"
+	defaultSMTPPort = 25
+
+	diagnosticsProbeAddr    = "gmail-smtp-in.l.google.com:25"
+	diagnosticsProbeTimeout = 5 * time.Second
 
 	reachableYes     = "yes"
 	reachableNo      = "no"
@@ -13,10 +18,16 @@ const (
 
 	alphanumeric = "abcdefghijklmnopqrstuvwxyz0123456789"
 
+	defaultHTTPTimeout = 10 * time.Second
+
 	disposableDataURL = "https://raw.githubusercontent.com/disposable/disposable-email-domains/master/domains.json"
 
-	gravatarBaseUrl    = "https://www.gravatar.com/avatar/"
-	gravatarDefaultMd5 = "d5fe5cbcc31cff5f8ac010db72eb000c"
+	gravatarBaseUrl        = "https://www.gravatar.com/avatar/"
+	gravatarProfileBaseUrl = "https://api.gravatar.com/v3/profiles/"
+	gravatarCacheTTL       = 24 * time.Hour
+	gravatarCacheMaxSize   = 100000
+
+	mxProviderCacheMaxSize = 10000
 
 	domainThreshold      float32 = 0.82
 	secondLevelThreshold float32 = 0.82
@@ -0,0 +1,231 @@
+package emailverifier
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	typeTLSA dnsmessage.Type = 52
+
+	// TLSA certificate usages, RFC 6698
+	tlsaUsagePKIXTA = 0
+	tlsaUsagePKIXEE = 1
+	tlsaUsageDANETA = 2
+	tlsaUsageDANEEE = 3
+
+	tlsaSelectorCert = 0
+	tlsaSelectorSPKI = 1
+
+	tlsaMatchFull   = 0
+	tlsaMatchSHA256 = 1
+	tlsaMatchSHA512 = 2
+
+	resolvConfPath = "/etc/resolv.conf"
+)
+
+type tlsaRecord struct {
+	usage        uint8
+	selector     uint8
+	matchingType uint8
+	data         []byte
+}
+
+// lookupTLSA queries the TLSA records of an MX host. The Go resolver has no TLSA
+// support, so the query goes straight to the system nameservers. authenticated reports
+// whether the resolver validated the answer with DNSSEC, without which the records
+// must not be used.
+func lookupTLSA(ctx context.Context, host string, port int) (records []tlsaRecord, authenticated bool, err error) {
+	name, err := dnsmessage.NewName("_" + strconv.Itoa(port) + "._tcp." + strings.TrimSuffix(host, ".") + ".")
+	if err != nil {
+		return nil, false, err
+	}
+	id := uint16(rand.Intn(1 << 16))
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{ID: id, RecursionDesired: true, AuthenticData: true})
+	b.EnableCompression()
+	if err = b.StartQuestions(); err != nil {
+		return nil, false, err
+	}
+	if err = b.Question(dnsmessage.Question{Name: name, Type: typeTLSA, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, false, err
+	}
+	query, err := b.Finish()
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, server := range systemNameservers() {
+		var resp []byte
+		resp, err = exchangeDNS(ctx, "udp", server, query)
+		if err != nil {
+			continue
+		}
+		var msg dnsmessage.Message
+		if err = msg.Unpack(resp); err != nil {
+			continue
+		}
+		if msg.Header.Truncated {
+			if resp, err = exchangeDNS(ctx, "tcp", server, query); err != nil {
+				continue
+			}
+			if err = msg.Unpack(resp); err != nil {
+				continue
+			}
+		}
+		if msg.Header.ID != id {
+			err = errors.New("dns response id mismatch")
+			continue
+		}
+		switch msg.Header.RCode {
+		case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+		default:
+			err = errors.New("dns query failed: " + msg.Header.RCode.String())
+			continue
+		}
+		for _, a := range msg.Answers {
+			body, ok := a.Body.(*dnsmessage.UnknownResource)
+			if !ok || a.Header.Type != typeTLSA || len(body.Data) < 3 {
+				continue
+			}
+			records = append(records, tlsaRecord{
+				usage:        body.Data[0],
+				selector:     body.Data[1],
+				matchingType: body.Data[2],
+				data:         body.Data[3:],
+			})
+		}
+		return records, msg.Header.AuthenticData, nil
+	}
+	if err == nil {
+		err = errors.New("no nameserver configured")
+	}
+	return nil, false, err
+}
+
+func exchangeDNS(ctx context.Context, network, server string, query []byte) ([]byte, error) {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if network == "udp" {
+		if _, err = conn.Write(query); err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+
+	msg := make([]byte, 2+len(query))
+	binary.BigEndian.PutUint16(msg, uint16(len(query)))
+	copy(msg[2:], query)
+	if _, err = conn.Write(msg); err != nil {
+		return nil, err
+	}
+	var length [2]byte
+	if _, err = io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+	resp := make([]byte, binary.BigEndian.Uint16(length[:]))
+	_, err = io.ReadFull(conn, resp)
+	return resp, err
+}
+
+func systemNameservers() []string {
+	f, err := os.Open(resolvConfPath)
+	if err != nil {
+		return []string{"127.0.0.1:53"}
+	}
+	defer f.Close()
+
+	var servers []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			servers = append(servers, net.JoinHostPort(fields[1], "53"))
+		}
+	}
+	if len(servers) == 0 {
+		return []string{"127.0.0.1:53"}
+	}
+	return servers
+}
+
+// matchTLSA reports whether one of the records matches the presented chain. PKIX usages
+// additionally require the chain to be valid for the host.
+func matchTLSA(records []tlsaRecord, chain []*x509.Certificate, pkixValid bool) bool {
+	if len(chain) == 0 {
+		return false
+	}
+	for _, r := range records {
+		var candidates []*x509.Certificate
+		switch r.usage {
+		case tlsaUsageDANEEE:
+			candidates = chain[:1]
+		case tlsaUsageDANETA:
+			candidates = chain[1:]
+		case tlsaUsagePKIXEE:
+			if pkixValid {
+				candidates = chain[:1]
+			}
+		case tlsaUsagePKIXTA:
+			if pkixValid {
+				candidates = chain[1:]
+			}
+		}
+		for _, cert := range candidates {
+			if tlsaMatches(r, cert) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func tlsaMatches(r tlsaRecord, cert *x509.Certificate) bool {
+	var data []byte
+	switch r.selector {
+	case tlsaSelectorCert:
+		data = cert.Raw
+	case tlsaSelectorSPKI:
+		data = cert.RawSubjectPublicKeyInfo
+	default:
+		return false
+	}
+	switch r.matchingType {
+	case tlsaMatchFull:
+	case tlsaMatchSHA256:
+		sum := sha256.Sum256(data)
+		data = sum[:]
+	case tlsaMatchSHA512:
+		sum := sha512.Sum512(data)
+		data = sum[:]
+	default:
+		return false
+	}
+	return bytes.Equal(data, r.data)
+}
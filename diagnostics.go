@@ -0,0 +1,278 @@
+package emailverifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var DefaultDNSBLZones = []string{
+	"zen.spamhaus.org",
+	"bl.spamcop.net",
+	"b.barracudacentral.org",
+}
+
+type DiagnosticsOptions struct {
+	// ProbeAddr is dialed to check port 25 egress, defaults to a Gmail MX.
+	ProbeAddr string
+	// ProbeTimeout bounds the port 25 dial, 5s by default. Filtered ports usually drop
+	// the connection attempt silently, so waiting longer only delays the report.
+	ProbeTimeout time.Duration
+	// DNSBLZones defaults to DefaultDNSBLZones.
+	DNSBLZones []string
+	// Timeout bounds the DNS checks of each IP, defaults to the connect timeout.
+	Timeout time.Duration
+}
+
+type SenderDiagnostics struct {
+	HelloName      string          `json:"hello_name"`
+	HelloNameAddrs []string        `json:"hello_name_addrs"`
+	IPs            []IPDiagnostics `json:"ips"`
+	Healthy        bool            `json:"healthy"`
+	CheckedAt      time.Time       `json:"checked_at"`
+}
+
+type IPDiagnostics struct {
+	IP                string         `json:"ip"`
+	BehindNAT         bool           `json:"behind_nat"`
+	Port25            bool           `json:"port25"`
+	Port25Error       string         `json:"port25_error,omitempty"`
+	PTR               []string       `json:"ptr"`
+	FCrDNS            bool           `json:"fcrdns"`
+	HelloMatchesPTR   bool           `json:"hello_matches_ptr"`
+	HelloResolvesToIP bool           `json:"hello_resolves_to_ip"`
+	Listings          []DNSBLListing `json:"listings"`
+	Problems          []string       `json:"problems"`
+}
+
+type DNSBLListing struct {
+	Zone   string   `json:"zone"`
+	Listed bool     `json:"listed"`
+	Codes  []string `json:"codes,omitempty"`
+	Reason string   `json:"reason,omitempty"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// DiagnoseSender checks whether the configured local IPs and HELO name are fit to run
+// SMTP verification: port 25 egress, forward-confirmed reverse DNS, HELO consistency
+// and DNSBL listings.
+func (v *Verifier) DiagnoseSender(opts *DiagnosticsOptions) *SenderDiagnostics {
+	if opts == nil {
+		opts = &DiagnosticsOptions{}
+	}
+	probeAddr := opts.ProbeAddr
+	if probeAddr == "" {
+		probeAddr = diagnosticsProbeAddr
+	}
+	zones := opts.DNSBLZones
+	if zones == nil {
+		zones = DefaultDNSBLZones
+	}
+	probeTimeout := opts.ProbeTimeout
+	if probeTimeout <= 0 {
+		probeTimeout = diagnosticsProbeTimeout
+	}
+	timeout := opts.Timeout
+	if timeout <= 0 {
+		timeout = v.connectTimeout
+	}
+
+	ret := &SenderDiagnostics{
+		HelloName: v.helloName,
+		CheckedAt: time.Now().UTC(),
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	helloAddrs, _ := net.DefaultResolver.LookupIPAddr(ctx, v.helloName)
+	cancel()
+	for _, a := range helloAddrs {
+		ret.HelloNameAddrs = append(ret.HelloNameAddrs, a.IP.String())
+	}
+
+	ips := v.sourceIPs()
+	if len(ips) == 0 {
+		if ip := outboundIP(probeAddr, timeout); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+
+	ret.IPs = make([]IPDiagnostics, len(ips))
+	var wg sync.WaitGroup
+	for i, ip := range ips {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			helloName, addrs := v.helloNameFor(ip), helloAddrs
+			if helloName != v.helloName {
+				addrs, _ = net.DefaultResolver.LookupIPAddr(ctx, helloName)
+			}
+			ret.IPs[i] = diagnoseIP(ctx, ip, helloName, addrs, probeAddr, probeTimeout, zones)
+		}()
+	}
+	wg.Wait()
+
+	ret.Healthy = len(ret.IPs) > 0
+	for _, d := range ret.IPs {
+		if len(d.Problems) > 0 {
+			ret.Healthy = false
+		}
+	}
+	return ret
+}
+
+// diagnoseIP runs the DNS checks on ctx while probing port 25 with its own timeout, so
+// a filtered port does not use up the time left for DNS.
+func diagnoseIP(ctx context.Context, ip net.IP, helloName string, helloAddrs []net.IPAddr, probeAddr string, probeTimeout time.Duration, zones []string) IPDiagnostics {
+	d := IPDiagnostics{IP: ip.String(), PTR: []string{}, Listings: []DNSBLListing{}, Problems: []string{}}
+
+	probed := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		defer cancel()
+		conn, err := establishConnection(ctx, probeAddr, ip)
+		if err == nil {
+			conn.Close()
+		}
+		probed <- err
+	}()
+
+	// reverse DNS and DNSBLs only make sense for the address receivers see, which a
+	// private one does not tell
+	d.BehindNAT = ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast()
+	if d.BehindNAT {
+		d.Problems = append(d.Problems, "behind NAT, public IP unknown")
+		waitProbe(&d, probed)
+		return d
+	}
+
+	names, _ := net.DefaultResolver.LookupAddr(ctx, ip.String())
+	for _, name := range names {
+		name = strings.TrimSuffix(name, ".")
+		d.PTR = append(d.PTR, name)
+		if strings.EqualFold(name, helloName) {
+			d.HelloMatchesPTR = true
+		}
+		addrs, _ := net.DefaultResolver.LookupIPAddr(ctx, name)
+		for _, a := range addrs {
+			if a.IP.Equal(ip) {
+				d.FCrDNS = true
+			}
+		}
+	}
+	for _, a := range helloAddrs {
+		if a.IP.Equal(ip) {
+			d.HelloResolvesToIP = true
+		}
+	}
+
+	waitProbe(&d, probed)
+
+	switch {
+	case len(d.PTR) == 0:
+		d.Problems = append(d.Problems, "no reverse DNS (PTR) record")
+	case !d.FCrDNS:
+		d.Problems = append(d.Problems, "reverse DNS is not forward-confirmed")
+	}
+	if !d.HelloResolvesToIP {
+		d.Problems = append(d.Problems, fmt.Sprintf("HELO name %s does not resolve to %s", helloName, ip))
+	}
+	if !d.HelloMatchesPTR && len(d.PTR) > 0 {
+		d.Problems = append(d.Problems, fmt.Sprintf("HELO name %s does not match reverse DNS %s", helloName, d.PTR[0]))
+	}
+
+	for _, zone := range zones {
+		l := checkDNSBL(ctx, ip, zone)
+		if l.Listed {
+			d.Problems = append(d.Problems, "listed on "+zone)
+		}
+		d.Listings = append(d.Listings, l)
+	}
+	return d
+}
+
+// waitProbe records the outcome of the port 25 probe on d.
+func waitProbe(d *IPDiagnostics, probed <-chan error) {
+	if err := <-probed; err != nil {
+		d.Port25Error = err.Error()
+		d.Problems = append(d.Problems, "outbound port 25 is blocked")
+	} else {
+		d.Port25 = true
+	}
+}
+
+func checkDNSBL(ctx context.Context, ip net.IP, zone string) DNSBLListing {
+	l := DNSBLListing{Zone: zone}
+	query := dnsblQuery(ip, zone)
+	addrs, err := net.DefaultResolver.LookupHost(ctx, query)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return l
+		}
+		l.Error = err.Error()
+		return l
+	}
+	for _, a := range addrs {
+		// 127.255.255.0/24 answers mean the query itself was refused, e.g. through
+		// a public resolver, and say nothing about the listing.
+		if strings.HasPrefix(a, "127.255.255.") {
+			l.Error = "query refused with " + a
+			return l
+		}
+		l.Codes = append(l.Codes, a)
+	}
+	l.Listed = len(l.Codes) > 0
+	if l.Listed {
+		if txt, err := net.DefaultResolver.LookupTXT(ctx, query); err == nil {
+			l.Reason = strings.Join(txt, " ")
+		}
+	}
+	return l
+}
+
+// dnsblQuery builds the reversed-octet (IPv4) or reversed-nibble (IPv6) query name.
+func dnsblQuery(ip net.IP, zone string) string {
+	var parts []string
+	if v4 := ip.To4(); v4 != nil {
+		for i := len(v4) - 1; i >= 0; i-- {
+			parts = append(parts, fmt.Sprintf("%d", v4[i]))
+		}
+	} else {
+		v6 := ip.To16()
+		for i := len(v6) - 1; i >= 0; i-- {
+			parts = append(parts, fmt.Sprintf("%x", v6[i]&0x0f), fmt.Sprintf("%x", v6[i]>>4))
+		}
+	}
+	return strings.Join(parts, ".") + "." + zone
+}
+
+// sourceIPs lists the local IPs verification connects from.
+func (v *Verifier) sourceIPs() []net.IP {
+	if v.ipPool != nil {
+		v.ipPool.mu.Lock()
+		defer v.ipPool.mu.Unlock()
+		ips := make([]net.IP, 0, len(v.ipPool.ips))
+		for _, s := range v.ipPool.ips {
+			ips = append(ips, s.ip)
+		}
+		return ips
+	}
+	if ip := parseLocalIP(v.localAddr); ip != nil {
+		return []net.IP{ip}
+	}
+	return nil
+}
+
+// outboundIP returns the local address the OS picks to reach addr. No packet is sent,
+// but resolving addr is bounded by timeout.
+func outboundIP(addr string, timeout time.Duration) net.IP {
+	conn, err := net.DialTimeout("udp", addr, timeout)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP
+}
@@ -0,0 +1,181 @@
+package emailverifier
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	FamilyHappyEyeballs = "happy_eyeballs"
+	FamilyPreferV4      = "prefer_v4"
+	FamilyV4Only        = "v4_only"
+	FamilyV6Only        = "v6_only"
+
+	// delay before racing the next address, as recommended by RFC 8305
+	happyEyeballsDelay = 250 * time.Millisecond
+)
+
+// smtpDialer holds everything needed to open a connection to an MX host.
+type smtpDialer struct {
+	ctx              context.Context
+	proxy            *proxyDialer
+	localIP          net.IP
+	port             int
+	family           string
+	connectTimeout   time.Duration
+	operationTimeout time.Duration
+	observe          func(Event)
+}
+
+func newDialEvent(mx *net.MX, remoteIP net.IP, d *smtpDialer, duration time.Duration, err error) DialEvent {
+	e := DialEvent{MX: mx.Host, Duration: duration, Err: err}
+	if remoteIP != nil {
+		e.RemoteIP = remoteIP.String()
+	}
+	if d.localIP != nil {
+		e.SourceIP = d.localIP.String()
+	}
+	if d.proxy != nil {
+		e.Proxy = d.proxy.redacted
+	}
+	return e
+}
+
+func (v *Verifier) AddressFamily(family string) *Verifier {
+	v.addressFamily = family
+	return v
+}
+
+// resolveMXHost resolves host to the addresses allowed by family, in the order they
+// should be tried. A source IP restricts the result to its own family.
+func resolveMXHost(ctx context.Context, host, family string, localIP net.IP) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+
+	network := "ip"
+	switch {
+	case family == FamilyV4Only || (localIP != nil && localIP.To4() != nil):
+		network = "ip4"
+	case family == FamilyV6Only || localIP != nil:
+		network = "ip6"
+	}
+	ips, err := net.DefaultResolver.LookupIP(ctx, network, strings.TrimSuffix(host, "."))
+	if err != nil {
+		return nil, err
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no %s address for %s", network, host)
+	}
+
+	var v4, v6 []net.IP
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			v4 = append(v4, ip)
+		} else {
+			v6 = append(v6, ip)
+		}
+	}
+	if family == FamilyPreferV4 {
+		return append(v4, v6...), nil
+	}
+	return interleaveFamilies(ips[0].To4() == nil, v4, v6), nil
+}
+
+// interleaveFamilies alternates address families starting with the one the resolver
+// returned first.
+func interleaveFamilies(v6First bool, v4, v6 []net.IP) []net.IP {
+	first, second := v4, v6
+	if v6First {
+		first, second = v6, v4
+	}
+	out := make([]net.IP, 0, len(v4)+len(v6))
+	for i := 0; i < len(first) || i < len(second); i++ {
+		if i < len(first) {
+			out = append(out, first[i])
+		}
+		if i < len(second) {
+			out = append(out, second[i])
+		}
+	}
+	return out
+}
+
+// dialAddresses connects to the first reachable address. With a stagger delay the next
+// address is raced after delay instead of waiting for the previous attempt to fail.
+func dialAddresses(ctx context.Context, ips []net.IP, port string, stagger time.Duration,
+	dial func(ctx context.Context, addr string) (net.Conn, error)) (net.Conn, net.IP, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan dialResult, len(ips))
+	start := func(ip net.IP) {
+		go func() {
+			conn, err := dial(ctx, net.JoinHostPort(ip.String(), port))
+			results <- dialResult{conn, ip, err}
+		}()
+	}
+
+	var firstErr error
+	next, pending := 0, 0
+	for {
+		if next < len(ips) && (pending == 0 || stagger > 0) {
+			start(ips[next])
+			next++
+			pending++
+		}
+
+		var timer <-chan time.Time
+		if stagger > 0 && next < len(ips) {
+			timer = time.After(stagger)
+		}
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				go drainConnections(results, pending)
+				return r.conn, r.ip, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if pending == 0 && next == len(ips) {
+				return nil, nil, firstErr
+			}
+		case <-timer:
+		case <-ctx.Done():
+			// the attempts give up with ctx, their errors tell which hop timed out
+			for ; pending > 0; pending-- {
+				r := <-results
+				if r.conn != nil {
+					r.conn.Close()
+				}
+				if firstErr == nil {
+					firstErr = r.err
+				}
+			}
+			if firstErr == nil {
+				firstErr = ctx.Err()
+			}
+			return nil, nil, firstErr
+		}
+	}
+}
+
+type dialResult struct {
+	conn net.Conn
+	ip   net.IP
+	err  error
+}
+
+// drainConnections closes connections of attempts that lost the race.
+func drainConnections(results chan dialResult, pending int) {
+	for i := 0; i < pending; i++ {
+		if r := <-results; r.conn != nil {
+			r.conn.Close()
+		}
+	}
+}
@@ -0,0 +1,371 @@
+package emailverifier
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	// RFC 7208 limits SPF evaluation to 10 DNS-querying terms
+	spfMaxLookups = 10
+	spfMaxDepth   = 10
+)
+
+var DefaultDKIMSelectors = []string{
+	"default", "dkim", "mail", "selector1", "selector2", "google", "k1", "k2", "k3",
+	"s1", "s2", "smtp", "mx", "email", "zoho", "mandrill", "everlytickey1", "mxvault",
+	"protonmail", "protonmail2", "protonmail3", "fm1", "fm2", "fm3", "sig1", "amazonses",
+}
+
+type DomainHealth struct {
+	Domain string  `json:"domain"`
+	SPF    *SPF    `json:"spf"`
+	DMARC  *DMARC  `json:"dmarc"`
+	DKIM   []DKIM  `json:"dkim"`
+	MTASTS *MTASTS `json:"mta_sts"`
+	TLSRPT *TLSRPT `json:"tls_rpt"`
+}
+
+type SPF struct {
+	Domain     string   `json:"domain"`
+	Record     string   `json:"record"`
+	Mechanisms []string `json:"mechanisms"`
+	All        string   `json:"all"`
+	Redirect   string   `json:"redirect,omitempty"`
+	Includes   []*SPF   `json:"includes,omitempty"`
+	// Lookups counts DNS-querying terms of the whole include tree.
+	Lookups int    `json:"lookups"`
+	Depth   int    `json:"depth"`
+	Error   string `json:"error,omitempty"`
+}
+
+type DMARC struct {
+	Record          string   `json:"record"`
+	Policy          string   `json:"policy"`
+	SubdomainPolicy string   `json:"subdomain_policy"`
+	Percent         int      `json:"percent"`
+	AggregateReport []string `json:"rua,omitempty"`
+	ForensicReport  []string `json:"ruf,omitempty"`
+	// Inherited is set when the record was found on the organizational domain.
+	Inherited bool   `json:"inherited"`
+	Error     string `json:"error,omitempty"`
+}
+
+type DKIM struct {
+	Selector string `json:"selector"`
+	KeyType  string `json:"key_type"`
+	Revoked  bool   `json:"revoked"`
+	Error    string `json:"error,omitempty"`
+}
+
+type TLSRPT struct {
+	Record          string   `json:"record"`
+	AggregateReport []string `json:"rua"`
+	Error           string   `json:"error,omitempty"`
+}
+
+type DomainHealthSummary struct {
+	SPF         bool   `json:"spf"`
+	SPFAll      string `json:"spf_all"`
+	DMARC       bool   `json:"dmarc"`
+	DMARCPolicy string `json:"dmarc_policy"`
+	DKIM        bool   `json:"dkim"`
+	MTASTS      string `json:"mta_sts"`
+	TLSRPT      bool   `json:"tls_rpt"`
+	// Score rates how seriously the domain handles mail from 0 to 100.
+	Score int `json:"score"`
+}
+
+func (v *Verifier) EnableDomainHealthCheck() *Verifier {
+	v.domainHealthEnabled = true
+	return v
+}
+
+func (v *Verifier) DisableDomainHealthCheck() *Verifier {
+	v.domainHealthEnabled = false
+	return v
+}
+
+// DKIMSelectors replaces the selectors probed by CheckDomainHealth. DKIM keys cannot be
+// enumerated, so only these selectors are found.
+func (v *Verifier) DKIMSelectors(selectors []string) *Verifier {
+	v.dkimSelectors = selectors
+	return v
+}
+
+func (v *Verifier) CheckDomainHealth(domain string) (*DomainHealth, error) {
+	domain = domainToASCII(strings.TrimSuffix(domain, "."))
+	if domain == "" {
+		return nil, errors.New("empty domain")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), v.operationTimeout)
+	defer cancel()
+
+	ret := DomainHealth{Domain: domain, DKIM: []DKIM{}}
+	errs := make([]error, 5)
+	var wg sync.WaitGroup
+	run := func(i int, f func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = f()
+		}()
+	}
+	run(0, func() (err error) { ret.SPF, err = lookupSPF(ctx, domain); return })
+	run(1, func() (err error) { ret.DMARC, err = lookupDMARC(ctx, domain); return })
+	run(2, func() (err error) { ret.DKIM, err = lookupDKIM(ctx, domain, v.dkimSelectors); return })
+	run(3, func() (err error) { ret.MTASTS, err = lookupMTASTS(ctx, domain, &v.http); return })
+	run(4, func() (err error) { ret.TLSRPT, err = lookupTLSRPT(ctx, domain); return })
+	wg.Wait()
+
+	// a partial report is still useful, but one without a single answer says nothing
+	// about the domain
+	for _, err := range errs {
+		if err == nil {
+			return &ret, nil
+		}
+	}
+	return nil, errs[0]
+}
+
+func (h *DomainHealth) Summary() *DomainHealthSummary {
+	s := &DomainHealthSummary{}
+	if h.SPF != nil && h.SPF.Error == "" {
+		s.SPF = true
+		s.SPFAll = h.SPF.All
+		s.Score += 20
+		if s.SPFAll == "-all" || s.SPFAll == "~all" {
+			s.Score += 5
+		}
+	}
+	if h.DMARC != nil && h.DMARC.Error == "" {
+		s.DMARC = true
+		s.DMARCPolicy = h.DMARC.Policy
+		s.Score += 20
+		if s.DMARCPolicy == "quarantine" || s.DMARCPolicy == "reject" {
+			s.Score += 10
+		}
+	}
+	for _, k := range h.DKIM {
+		if !k.Revoked && k.Error == "" {
+			s.DKIM = true
+			s.Score += 20
+			break
+		}
+	}
+	if h.MTASTS != nil && h.MTASTS.Error == "" {
+		s.MTASTS = h.MTASTS.Mode
+		if s.MTASTS != MTASTSModeNone {
+			s.Score += 15
+		}
+	}
+	if h.TLSRPT != nil && h.TLSRPT.Error == "" {
+		s.TLSRPT = true
+		s.Score += 10
+	}
+	return s
+}
+
+// spfWalk is shared by the records of one SPF evaluation.
+type spfWalk struct {
+	lookups int
+	visited map[string]bool
+	err     error
+}
+
+// lookupSPF returns nil when the domain publishes no SPF record. The error is the one of
+// the domain's own record lookup, which is also kept on the returned SPF.
+func lookupSPF(ctx context.Context, domain string) (*SPF, error) {
+	w := &spfWalk{visited: map[string]bool{}}
+	spf := w.lookup(ctx, strings.ToLower(domain), 0)
+	return spf, w.err
+}
+
+// lookup follows includes and redirects until the evaluation runs out of lookups, and
+// each domain only once, so loops and wide include trees stay bounded.
+func (w *spfWalk) lookup(ctx context.Context, domain string, depth int) *SPF {
+	w.visited[domain] = true
+	record, err := lookupTaggedTXT(ctx, domain, "v=spf1")
+	if err != nil {
+		if depth == 0 {
+			w.err = err
+		}
+		return &SPF{Domain: domain, Depth: depth, Error: err.Error()}
+	}
+	if record == "" {
+		if depth == 0 {
+			return nil
+		}
+		return &SPF{Domain: domain, Depth: depth, Error: "no spf record"}
+	}
+
+	spf := &SPF{Domain: domain, Record: record, Mechanisms: []string{}, Depth: depth}
+	for _, term := range strings.Fields(record)[1:] {
+		term = strings.ToLower(term)
+		mechanism := strings.TrimLeft(term, "+-~?")
+		name, value, _ := strings.Cut(mechanism, ":")
+		if strings.HasPrefix(name, "redirect=") {
+			name, value = "redirect", strings.TrimPrefix(name, "redirect=")
+		}
+		switch name {
+		case "all":
+			spf.All = term
+			if spf.All == "all" {
+				spf.All = "+all"
+			}
+		case "include", "redirect":
+			spf.Lookups++
+			w.lookups++
+			if name == "redirect" {
+				spf.Redirect = value
+			}
+			if w.lookups > spfMaxLookups || w.visited[value] {
+				break
+			}
+			if depth+1 >= spfMaxDepth {
+				spf.Error = "include depth exceeded"
+				break
+			}
+			child := w.lookup(ctx, value, depth+1)
+			spf.Includes = append(spf.Includes, child)
+			spf.Lookups += child.Lookups
+			if child.Depth > spf.Depth {
+				spf.Depth = child.Depth
+			}
+			if name == "redirect" && spf.All == "" {
+				spf.All = child.All
+			}
+		case "a", "mx", "ptr", "exists":
+			spf.Lookups++
+			w.lookups++
+		}
+		spf.Mechanisms = append(spf.Mechanisms, term)
+	}
+	if depth == 0 && w.lookups > spfMaxLookups && spf.Error == "" {
+		spf.Error = "too many dns lookups (" + strconv.Itoa(spf.Lookups) + ")"
+	}
+	return spf
+}
+
+// lookupDMARC falls back to the organizational domain as described in RFC 7489, found
+// with the public suffix list. Failed lookups are returned with their Error set, only a
+// missing record returns nil.
+func lookupDMARC(ctx context.Context, domain string) (*DMARC, error) {
+	record, err := lookupTaggedTXT(ctx, "_dmarc."+domain, "v=DMARC1")
+	inherited := false
+	if err == nil && record == "" {
+		if org, psErr := publicsuffix.EffectiveTLDPlusOne(domain); psErr == nil && org != domain {
+			record, err = lookupTaggedTXT(ctx, "_dmarc."+org, "v=DMARC1")
+			inherited = true
+		}
+	}
+	if err != nil {
+		return &DMARC{Error: err.Error()}, err
+	}
+	if record == "" {
+		return nil, nil
+	}
+
+	tags := parseTagList(record)
+	dmarc := &DMARC{
+		Record:          record,
+		Policy:          strings.ToLower(tags["p"]),
+		SubdomainPolicy: strings.ToLower(tags["sp"]),
+		Percent:         100,
+		AggregateReport: splitURIList(tags["rua"]),
+		ForensicReport:  splitURIList(tags["ruf"]),
+		Inherited:       inherited,
+	}
+	if dmarc.SubdomainPolicy == "" {
+		dmarc.SubdomainPolicy = dmarc.Policy
+	}
+	if pct, err := strconv.Atoi(tags["pct"]); err == nil {
+		dmarc.Percent = pct
+	}
+	return dmarc, nil
+}
+
+// lookupDKIM returns the selectors that publish a key, and those whose lookup failed
+// with their Error set. The error is only returned when every lookup failed.
+func lookupDKIM(ctx context.Context, domain string, selectors []string) ([]DKIM, error) {
+	if selectors == nil {
+		selectors = DefaultDKIMSelectors
+	}
+	found := make([]*DKIM, len(selectors))
+	errs := make([]error, len(selectors))
+	var wg sync.WaitGroup
+	for i, selector := range selectors {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			records, err := net.DefaultResolver.LookupTXT(ctx, selector+"._domainkey."+domain)
+			if err != nil {
+				if dnsErr, ok := err.(*net.DNSError); !ok || !dnsErr.IsNotFound {
+					errs[i] = err
+					found[i] = &DKIM{Selector: selector, Error: err.Error()}
+				}
+				return
+			}
+			if len(records) == 0 {
+				return
+			}
+			// long keys are split over several strings
+			tags := parseTagList(strings.Join(records, ""))
+			p, ok := tags["p"]
+			if !ok {
+				return
+			}
+			keyType := tags["k"]
+			if keyType == "" {
+				keyType = "rsa"
+			}
+			found[i] = &DKIM{Selector: selector, KeyType: keyType, Revoked: p == ""}
+		}()
+	}
+	wg.Wait()
+
+	ret := []DKIM{}
+	for _, k := range found {
+		if k != nil {
+			ret = append(ret, *k)
+		}
+	}
+	if len(errs) == 0 {
+		return ret, nil
+	}
+	for _, err := range errs {
+		if err == nil {
+			return ret, nil
+		}
+	}
+	return ret, errs[0]
+}
+
+// lookupTLSRPT returns nil when the domain publishes no TLS-RPT record.
+func lookupTLSRPT(ctx context.Context, domain string) (*TLSRPT, error) {
+	record, err := lookupTaggedTXT(ctx, "_smtp._tls."+domain, "v=TLSRPTv1")
+	if err != nil {
+		return &TLSRPT{Error: err.Error()}, err
+	}
+	if record == "" {
+		return nil, nil
+	}
+	return &TLSRPT{Record: record, AggregateReport: splitURIList(parseTagList(record)["rua"])}, nil
+}
+
+func splitURIList(s string) []string {
+	var uris []string
+	for _, u := range strings.Split(s, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			uris = append(uris, u)
+		}
+	}
+	return uris
+}
@@ -1,7 +1,9 @@
 package emailverifier
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -20,19 +22,155 @@ const (
 	ErrNotAllowed              = "Not Allowed"
 	ErrNeedMAILBeforeRCPT      = "Need MAIL before RCPT"
 	ErrRCPTHasMoved            = "Recipient has moved"
-)
+)
+
+// ErrorCode is the stable, machine readable counterpart of the messages above. Codes
+// are errors themselves, so errors.Is(err, ErrCodeBlocked) matches any *LookupError
+// carrying that code.
+type ErrorCode string
+
+const (
+	ErrCodeTimeout                 ErrorCode = "timeout"
+	ErrCodeNoSuchHost              ErrorCode = "no_such_host"
+	ErrCodeServerUnavailable       ErrorCode = "server_unavailable"
+	ErrCodeBlocked                 ErrorCode = "blocked"
+	ErrCodeTryAgainLater           ErrorCode = "try_again_later"
+	ErrCodeFullInbox               ErrorCode = "full_inbox"
+	ErrCodeTooManyRCPT             ErrorCode = "too_many_rcpt"
+	ErrCodeNoRelay                 ErrorCode = "no_relay"
+	ErrCodeMailboxBusy             ErrorCode = "mailbox_busy"
+	ErrCodeExceededMessagingLimits ErrorCode = "exceeded_messaging_limits"
+	ErrCodeNotAllowed              ErrorCode = "not_allowed"
+	ErrCodeNeedMAILBeforeRCPT      ErrorCode = "need_mail_before_rcpt"
+	ErrCodeRCPTHasMoved            ErrorCode = "rcpt_has_moved"
+	ErrCodeUnknown                 ErrorCode = "unknown"
+)
+
+func (c ErrorCode) Error() string {
+	return string(c)
+}
+
+// Stages of a verification an error can occur in.
+const (
+	StageDNS      = "dns"
+	StageConnect  = "connect"
+	StageHELO     = "helo"
+	StageTLS      = "tls"
+	StageMAIL     = "mail"
+	StageRCPT     = "rcpt"
+	StageGravatar = "gravatar"
+)
+
+var messageCodes = map[string]ErrorCode{
+	ErrTimeout:                 ErrCodeTimeout,
+	ErrNoSuchHost:              ErrCodeNoSuchHost,
+	ErrServerUnavailable:       ErrCodeServerUnavailable,
+	ErrBlocked:                 ErrCodeBlocked,
+	ErrTryAgainLater:           ErrCodeTryAgainLater,
+	ErrFullInbox:               ErrCodeFullInbox,
+	ErrTooManyRCPT:             ErrCodeTooManyRCPT,
+	ErrNoRelay:                 ErrCodeNoRelay,
+	ErrMailboxBusy:             ErrCodeMailboxBusy,
+	ErrExceededMessagingLimits: ErrCodeExceededMessagingLimits,
+	ErrNotAllowed:              ErrCodeNotAllowed,
+	ErrNeedMAILBeforeRCPT:      ErrCodeNeedMAILBeforeRCPT,
+	ErrRCPTHasMoved:            ErrCodeRCPTHasMoved,
+}
+
+var enhancedCodeRegex = regexp.MustCompile(`^\d{3}[ -]"?([245]\.\d{1,3}\.\d{1,3})\b`)
+
 type LookupError struct {
 	Message string `json:"message" xml:"message"`
 	Details string `json:"details" xml:"details"`
-}
+
+	Code         ErrorCode `json:"code,omitempty" xml:"code,omitempty"`
+	SMTPCode     int       `json:"smtp_code,omitempty" xml:"smtp_code,omitempty"`
+	EnhancedCode string    `json:"enhanced_code,omitempty" xml:"enhanced_code,omitempty"`
+	Stage        string    `json:"stage,omitempty" xml:"stage,omitempty"`
+	Retryable    bool      `json:"retryable,omitempty" xml:"retryable,omitempty"`
+
+	// Err is the underlying error, reachable through errors.As and errors.Unwrap.
+	Err error `json:"-" xml:"-"`
+}
 func newLookupError(message, details string) *LookupError {
-	return &LookupError{message, details}
+	code, ok := messageCodes[message]
+	if !ok {
+		code = ErrCodeUnknown
+	}
+	e := &LookupError{Message: message, Details: details, Code: code}
+	if status, err := strconv.Atoi(firstRunes(details, 3)); err == nil && status >= 200 && status < 600 {
+		e.SMTPCode = status
+		if m := enhancedCodeRegex.FindStringSubmatch(details); m != nil {
+			e.EnhancedCode = m[1]
+		}
+	}
+	e.Retryable = isRetryable(e)
+	return e
 }
 
 func (e *LookupError) Error() string {
 	return fmt.Sprintf("%s : %s", e.Message, e.Details)
-}
+}
+
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+// Is matches ErrorCode sentinels, and other *LookupErrors with the same code.
+func (e *LookupError) Is(target error) bool {
+	switch t := target.(type) {
+	case ErrorCode:
+		return e.Code == t
+	case *LookupError:
+		return t.Code != "" && e.Code == t.Code
+	}
+	return false
+}
+
+func isRetryable(e *LookupError) bool {
+	switch e.Code {
+	case ErrCodeTimeout, ErrCodeTryAgainLater, ErrCodeMailboxBusy, ErrCodeExceededMessagingLimits, ErrCodeTooManyRCPT:
+		return true
+	}
+	return e.SMTPCode >= 400 && e.SMTPCode < 500
+}
+
+// parseErrorAt classifies err like ParseSMTPError and records the stage it happened in.
+// Replies ParseSMTPError leaves unclassified, e.g. an unexpected 2xx, get ErrCodeUnknown.
+func parseErrorAt(err error, stage string) *LookupError {
+	e := ParseSMTPError(err)
+	if e == nil {
+		e = newLookupError(err.Error(), err.Error())
+		e.Err = err
+	}
+	if e.Stage == "" {
+		e.Stage = stage
+	}
+	return e
+}
+
+func firstRunes(s string, n int) string {
+	r := []rune(s)
+	if len(r) < n {
+		return s
+	}
+	return string(r[:n])
+}
+
+// ParseSMTPError classifies err. *LookupErrors are returned as they are.
 func ParseSMTPError(err error) *LookupError {
+	var lookupErr *LookupError
+	if errors.As(err, &lookupErr) {
+		return lookupErr
+	}
+	e := parseSMTPError(err)
+	if e != nil && e.Err == nil {
+		e.Err = err
+	}
+	return e
+}
+
+func parseSMTPError(err error) *LookupError {
 	errStr := err.Error()
 	if len(errStr) < 3 {
 		return parseBasicErr(err)
@@ -0,0 +1,134 @@
+package emailverifier
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnhancedCode(t *testing.T) {
+	tests := []struct {
+		details      string
+		smtpCode     int
+		enhancedCode string
+	}{
+		{"550 5.1.1 The email account that you tried to reach does not exist", 550, "5.1.1"},
+		{"550-5.7.1 Service unavailable, client host blocked", 550, "5.7.1"},
+		{"421 4.7.0 Try again later, closing connection", 421, "4.7.0"},
+		{"452 4.2.2 Mailbox full", 452, "4.2.2"},
+		{`550 "5.1.10" RESOLVER.ADR.RecipientNotFound`, 550, "5.1.10"},
+		{"250 2.1.5 Ok", 250, "2.1.5"},
+		{"550 No such user here", 550, ""},
+		{"550 6.1.1 not a status class", 550, ""},
+		{"550 5.1.1234 too many digits", 550, ""},
+		{"dial tcp: i/o timeout", 0, ""},
+		{"999 5.1.1 not a reply code", 0, ""},
+	}
+	for _, tt := range tests {
+		e := newLookupError(ErrServerUnavailable, tt.details)
+		if e.SMTPCode != tt.smtpCode || e.EnhancedCode != tt.enhancedCode {
+			t.Errorf("newLookupError(%q) = %d %q, want %d %q", tt.details, e.SMTPCode, e.EnhancedCode, tt.smtpCode, tt.enhancedCode)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		message string
+		details string
+		want    bool
+	}{
+		{ErrTimeout, "dial tcp 192.0.2.1:25: i/o timeout", true},
+		{ErrTryAgainLater, "421 4.7.0 Try again later", true},
+		{ErrMailboxBusy, "450 4.2.1 Mailbox busy", true},
+		{ErrExceededMessagingLimits, "451 4.7.1 Rate limited", true},
+		{ErrTooManyRCPT, "452 4.5.3 Too many recipients", true},
+		{ErrFullInbox, "452 4.2.2 Over quota", true},
+		{ErrServerUnavailable, "450 4.1.1 Recipient address rejected: unverified", true},
+		{ErrFullInbox, "552 5.2.2 Mailbox full", false},
+		{ErrServerUnavailable, "550 5.1.1 User unknown", false},
+		{ErrBlocked, "550 5.7.1 Blocked by Spamhaus", false},
+		{ErrNoSuchHost, "lookup example.invalid: no such host", false},
+		{"connection refused", "connection refused", false},
+	}
+	for _, tt := range tests {
+		if got := newLookupError(tt.message, tt.details).Retryable; got != tt.want {
+			t.Errorf("Retryable(%q, %q) = %v, want %v", tt.message, tt.details, got, tt.want)
+		}
+	}
+}
+
+func TestLookupErrorIs(t *testing.T) {
+	err := error(newLookupError(ErrBlocked, "550 5.7.1 Blocked"))
+	if !errors.Is(err, ErrCodeBlocked) {
+		t.Errorf("errors.Is(%v, ErrCodeBlocked) = false", err)
+	}
+	if errors.Is(err, ErrCodeTimeout) {
+		t.Errorf("errors.Is(%v, ErrCodeTimeout) = true", err)
+	}
+	if !errors.Is(err, &LookupError{Code: ErrCodeBlocked}) {
+		t.Errorf("errors.Is(%v, &LookupError{Code: blocked}) = false", err)
+	}
+}
+
+func TestParseSMTPError(t *testing.T) {
+	tests := []struct {
+		err  string
+		want ErrorCode
+	}{
+		{"550 5.1.1 The email account that you tried to reach does not exist", ErrCodeServerUnavailable},
+		{"450 4.1.1 <john@example.com>: Recipient address rejected", ErrCodeServerUnavailable},
+		{"550 5.7.1 Service unavailable; client host blocked using Spamhaus", ErrCodeBlocked},
+		{"550 5.7.1 Relaying denied", ErrCodeBlocked},
+		{"550 Mailbox unavailable", ErrCodeServerUnavailable},
+		{"421 4.7.0 Try again later", ErrCodeTryAgainLater},
+		{"450 4.2.1 Mailbox busy", ErrCodeMailboxBusy},
+		{"451 4.7.1 Please slow down", ErrCodeExceededMessagingLimits},
+		{"452 4.2.2 Mailbox full", ErrCodeFullInbox},
+		{"452 4.5.3 Too many recipients", ErrCodeTooManyRCPT},
+		{"503 5.5.1 Need MAIL command", ErrCodeNeedMAILBeforeRCPT},
+		{"551 5.1.6 User has moved", ErrCodeRCPTHasMoved},
+		{"552 5.2.2 Over quota", ErrCodeFullInbox},
+		{"553 5.7.1 Not an open relay", ErrCodeNoRelay},
+		{"554 5.7.1 Rejected", ErrCodeNotAllowed},
+		{"500 5.5.2 Syntax error", ErrCodeUnknown},
+		{"dial tcp 192.0.2.1:25: i/o timeout", ErrCodeTimeout},
+		{"lookup example.invalid: no such host", ErrCodeNoSuchHost},
+		{"EOF", ErrCodeUnknown},
+	}
+	for _, tt := range tests {
+		e := parseSMTPError(errors.New(tt.err))
+		if e == nil {
+			t.Errorf("parseSMTPError(%q) = nil, want %s", tt.err, tt.want)
+			continue
+		}
+		if e.Code != tt.want {
+			t.Errorf("parseSMTPError(%q).Code = %s, want %s", tt.err, e.Code, tt.want)
+		}
+	}
+
+	// replies that are not errors are left to the caller
+	for _, reply := range []string{"250 2.1.0 Ok", "251 sender ok", "400 odd"} {
+		if e := parseSMTPError(errors.New(reply)); e != nil {
+			t.Errorf("parseSMTPError(%q) = %v, want nil", reply, e)
+		}
+	}
+}
+
+func TestParseErrorAt(t *testing.T) {
+	e := parseErrorAt(errors.New("251 sender ok"), StageMAIL)
+	if e == nil {
+		t.Fatal("parseErrorAt(251) = nil")
+	}
+	if e.Code != ErrCodeUnknown || e.Stage != StageMAIL || e.Details != "251 sender ok" {
+		t.Errorf("parseErrorAt(251) = %+v", e)
+	}
+	if f := newFailure(e); f == nil || f.Stage != StageMAIL {
+		t.Errorf("newFailure(%+v) = %+v", e, f)
+	}
+
+	// errors classified before keep their stage
+	e = parseErrorAt(parseErrorAt(errors.New("421 4.7.0 Try again later"), StageConnect), StageRCPT)
+	if e.Code != ErrCodeTryAgainLater || e.Stage != StageConnect {
+		t.Errorf("parseErrorAt(LookupError) = %+v", e)
+	}
+}
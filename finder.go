@@ -0,0 +1,215 @@
+package emailverifier
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// finderPatterns are the local part conventions tried by FindEmail, most common first,
+// scored by how widespread they are. A pattern is also its template: "first" and "last"
+// stand for the names, "f" and "l" for their initials.
+var finderPatterns = []struct {
+	pattern string
+	score   float64
+}{
+	{NamePatternFirstLast, 0.9},
+	{NamePatternFirst, 0.8},
+	{NamePatternInitialLastJoined, 0.75},
+	{NamePatternFirstLastJoined, 0.7},
+	{NamePatternInitialLast, 0.65},
+	{"first_last", 0.6},
+	{"firstl", 0.5},
+	{NamePatternFirstInitial, 0.45},
+	{NamePatternLast, 0.4},
+	{"last.first", 0.35},
+	{"first-last", 0.3},
+	{"lastf", 0.3},
+	{NamePatternLastFirst, 0.25},
+	{NamePatternLastFirstJoined, 0.2},
+	{"fl", 0.1},
+}
+
+// nameFolding covers letters that do not decompose into an ASCII letter and a mark.
+var nameFolding = strings.NewReplacer("ß", "ss", "æ", "ae", "œ", "oe", "ø", "o", "ł", "l", "đ", "d", "ð", "d", "þ", "th", "ı", "i")
+
+var errFinderInput = errors.New("a first name and a valid domain are required")
+
+// FinderResult holds the addresses guessed for a person at a domain. Email is the
+// confirmed address, if any. No candidates are returned for catch-all domains, as
+// accepting a guess tells nothing there.
+type FinderResult struct {
+	Domain             string       `json:"domain"`
+	Email              string       `json:"email"`
+	CatchAll           bool         `json:"catch_all"`
+	CatchAllConfidence float64      `json:"catch_all_confidence"`
+	Candidates         []*Candidate `json:"candidates"`
+	Failure            *Failure     `json:"failure"`
+}
+
+// Candidate is a guessed address. Score is the likelihood from the pattern's popularity
+// until the address was checked, then 1 for an accepted and 0 for a rejected one.
+type Candidate struct {
+	Email       string  `json:"email"`
+	Pattern     string  `json:"pattern"`
+	Score       float64 `json:"score"`
+	Checked     bool    `json:"checked"`
+	Deliverable bool    `json:"deliverable"`
+}
+
+// FindEmail guesses the address of firstName lastName at domain. The candidates are
+// checked in one SMTP session, most likely first, until one is accepted. Without SMTP
+// checks enabled the candidates are only ranked.
+func (v *Verifier) FindEmail(firstName, lastName, domain string) (*FinderResult, error) {
+	domain = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(domain), "."))
+	ret := &FinderResult{
+		Domain:     domain,
+		Candidates: emailCandidates(normalizeName(firstName), normalizeName(lastName), domain),
+	}
+	if len(ret.Candidates) == 0 || !IsAddressValid(ret.Candidates[0].Email) {
+		return nil, errFinderInput
+	}
+	if !v.smtpCheckEnabled {
+		return ret, nil
+	}
+
+	if e := v.checkCandidates(ret); e != nil {
+		ret.Failure = newFailure(e)
+		if v.strictErrors {
+			return ret, e
+		}
+	}
+	slices.SortStableFunc(ret.Candidates, func(a, b *Candidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return ret, nil
+}
+
+func (v *Verifier) checkCandidates(ret *FinderResult) *LookupError {
+	var smtp SMTP
+	s, err := v.startSMTP(context.Background(), ret.Domain, &smtp, nil)
+	if err != nil {
+		return ParseSMTPError(err)
+	}
+	defer s.close()
+
+	if s.api != nil {
+		for _, c := range ret.Candidates {
+			res, err := s.api.check(ret.Domain, strings.TrimSuffix(c.Email, "@"+ret.Domain))
+			if err != nil {
+				return parseErrorAt(err, StageRCPT)
+			}
+			if ret.found(c, res.Deliverable) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	probes := probeCatchAll(s.client, ret.Domain, v.catchAllProbes)
+	for _, p := range probes {
+		s.flagBlocked(p.err, false)
+	}
+	if accepted, rejected := countProbes(probes); accepted+rejected == 0 {
+		return probes[len(probes)-1].err
+	}
+	ret.CatchAllConfidence = catchAllConfidence(probes, nil)
+	if ret.CatchAll = isCatchAll(ret.CatchAllConfidence); ret.CatchAll {
+		ret.Candidates = []*Candidate{}
+		return nil
+	}
+
+	for _, c := range ret.Candidates {
+		p := s.client.probeRcpt(c.Email)
+		s.flagBlocked(p.err, false)
+		switch {
+		case p.accepted || p.rejected():
+			if ret.found(c, p.accepted) {
+				return nil
+			}
+		case p.err.Code == ErrCodeFullInbox:
+			// the mailbox exists but tells nothing about the other candidates
+			c.Checked = true
+		default:
+			return p.err
+		}
+	}
+	return nil
+}
+
+// found records the answer for c and reports whether the search is over.
+func (r *FinderResult) found(c *Candidate, deliverable bool) bool {
+	c.Checked = true
+	c.Deliverable = deliverable
+	if !deliverable {
+		c.Score = 0
+		return false
+	}
+	c.Score = 1
+	r.Email = c.Email
+	return true
+}
+
+// emailCandidates expands finderPatterns for the names. Without a last name only the
+// patterns using the first name alone are kept.
+func emailCandidates(first, last, domain string) []*Candidate {
+	if first == "" {
+		return nil
+	}
+	candidates := make([]*Candidate, 0, len(finderPatterns))
+	seen := map[string]bool{}
+	for _, p := range finderPatterns {
+		local, ok := expandPattern(p.pattern, first, last)
+		if !ok || seen[local] {
+			continue
+		}
+		seen[local] = true
+		candidates = append(candidates, &Candidate{Email: local + "@" + domain, Pattern: p.pattern, Score: p.score})
+	}
+	return candidates
+}
+
+func expandPattern(pattern, first, last string) (string, bool) {
+	var b strings.Builder
+	for rest := pattern; rest != ""; {
+		var name string
+		switch {
+		case strings.HasPrefix(rest, "first"):
+			name, rest = first, rest[len("first"):]
+		case strings.HasPrefix(rest, "last"):
+			name, rest = last, rest[len("last"):]
+		case rest[0] == 'f':
+			name, rest = first[:1], rest[1:]
+		case rest[0] == 'l':
+			if last == "" {
+				return "", false
+			}
+			name, rest = last[:1], rest[1:]
+		default:
+			b.WriteByte(rest[0])
+			rest = rest[1:]
+			continue
+		}
+		if name == "" {
+			return "", false
+		}
+		b.WriteString(name)
+	}
+	return b.String(), true
+}
+
+// normalizeName lowercases name and reduces it to ASCII letters, dropping accents,
+// spaces and punctuation, e.g. "Dupont-Aignan" becomes "dupontaignan".
+func normalizeName(name string) string {
+	name = nameFolding.Replace(strings.ToLower(norm.NFD.String(name)))
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' {
+			return r
+		}
+		return -1
+	}, name)
+}
@@ -0,0 +1,192 @@
+package emailverifier
+
+import (
+	"bytes"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// servers taking this long to greet or answer EHLO are slowing clients down on purpose
+const tarpitDelay = 5 * time.Second
+
+// SMTPServer describes the server from its greeting and EHLO reply. Provider is set when
+// the server is recognized as a hosted mail service, whatever its MX host is called.
+type SMTPServer struct {
+	Banner          string   `json:"banner"`
+	Hostname        string   `json:"hostname"`
+	Software        string   `json:"software,omitempty"`
+	Provider        string   `json:"provider,omitempty"`
+	Capabilities    []string `json:"capabilities"`
+	MaxSize         int64    `json:"max_size,omitempty"`
+	SMTPUTF8        bool     `json:"smtputf8"`
+	Pipelining      bool     `json:"pipelining"`
+	GreetingDelayMs int64    `json:"greeting_delay_ms"`
+	Tarpit          bool     `json:"tarpit"`
+}
+
+var smtpFingerprints = []struct {
+	pattern  *regexp.Regexp
+	software string
+	provider string
+}{
+	{regexp.MustCompile(`(?i)\bmx\.google\.com\b|\bgsmtp\b`), "google", "google.com"},
+	{regexp.MustCompile(`(?i)\.protection\.outlook\.com\b`), "exchange_online", "outlook.com"},
+	{regexp.MustCompile(`(?i)\.yahoodns\.net\b`), "yahoo", "yahoodns.net"},
+	{regexp.MustCompile(`(?i)\.pphosted\.com\b|\bproofpoint\b`), "proofpoint", "pphosted.com"},
+	{regexp.MustCompile(`(?i)\bmimecast\b`), "mimecast", "mimecast.com"},
+	{regexp.MustCompile(`(?i)\bbarracuda\b`), "barracuda", "barracudanetworks.com"},
+	{regexp.MustCompile(`(?i)\.zoho\.(com|eu|in)\b`), "zoho", "zoho.com"},
+	{regexp.MustCompile(`(?i)\.mail\.icloud\.com\b`), "icloud", "icloud.com"},
+	{regexp.MustCompile(`(?i)\.yandex\.(net|ru)\b`), "yandex", "yandex.net"},
+	{regexp.MustCompile(`(?i)Microsoft ESMTP MAIL Service`), "exchange", ""},
+	{regexp.MustCompile(`(?i)\bPostfix\b`), "postfix", ""},
+	{regexp.MustCompile(`(?i)\bExim\b`), "exim", ""},
+	{regexp.MustCompile(`(?i)\bSendmail\b`), "sendmail", ""},
+	{regexp.MustCompile(`(?i)\bOpenSMTPD\b`), "opensmtpd", ""},
+	{regexp.MustCompile(`(?i)\bHaraka\b`), "haraka", ""},
+	{regexp.MustCompile(`(?i)\bMDaemon\b`), "mdaemon", ""},
+	{regexp.MustCompile(`(?i)\bhMailServer\b`), "hmailserver", ""},
+	{regexp.MustCompile(`(?i)\bKerio\b`), "kerio", ""},
+	{regexp.MustCompile(`(?i)\bZimbra\b`), "zimbra", ""},
+}
+
+// mxProviderSyncCache maps the registered domain of MX hosts to the provider recognized
+// from their fingerprint, when it differs.
+var (
+	mxProviderSyncCache sync.Map
+	mxProviderCacheSize int64
+)
+
+// detectProvider returns the provider recognized the last time an MX host of the same
+// registered domain was connected to, or the registered domain of host.
+func detectProvider(host string) string {
+	key := mxProvider(host)
+	if provider, ok := mxProviderSyncCache.Load(key); ok {
+		return provider.(string)
+	}
+	return key
+}
+
+// storeProvider remembers the provider fingerprinted on host. Self-hosted domains each
+// add an entry, so the cache starts over once it is full.
+func storeProvider(host, provider string) {
+	key := mxProvider(host)
+	if provider == key {
+		return
+	}
+	if atomic.LoadInt64(&mxProviderCacheSize) >= mxProviderCacheMaxSize {
+		mxProviderSyncCache.Range(func(key, value interface{}) bool {
+			if mxProviderSyncCache.CompareAndDelete(key, value) {
+				atomic.AddInt64(&mxProviderCacheSize, -1)
+			}
+			return true
+		})
+	}
+	if _, loaded := mxProviderSyncCache.Swap(key, provider); !loaded {
+		atomic.AddInt64(&mxProviderCacheSize, 1)
+	}
+}
+
+// recordingConn keeps a copy of what is read while recording, as net/smtp parses the
+// greeting and EHLO reply without exposing them.
+type recordingConn struct {
+	net.Conn
+	buf *bytes.Buffer
+}
+
+func (c *recordingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if c.buf != nil {
+		c.buf.Write(p[:n])
+	}
+	return n, err
+}
+
+func (c *recordingConn) record() {
+	c.buf = &bytes.Buffer{}
+}
+
+func (c *recordingConn) stop() string {
+	if c.buf == nil {
+		return ""
+	}
+	s := c.buf.String()
+	c.buf = nil
+	return s
+}
+
+// lastReplyLines returns the text lines of the last reply in raw, without reply codes.
+func lastReplyLines(raw string) []string {
+	var lines []string
+	final := false
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if len(line) < 3 {
+			continue
+		}
+		if final {
+			lines, final = nil, false
+		}
+		lines = append(lines, line[min(len(line), 4):])
+		final = len(line) == 3 || line[3] == ' '
+	}
+	return lines
+}
+
+func newSMTPServer(greeting []string, delay time.Duration) *SMTPServer {
+	s := &SMTPServer{
+		Banner:          strings.Join(greeting, " "),
+		Capabilities:    []string{},
+		GreetingDelayMs: delay.Milliseconds(),
+		Tarpit:          delay >= tarpitDelay,
+	}
+	if fields := strings.Fields(s.Banner); len(fields) > 0 {
+		s.Hostname = fields[0]
+	}
+	s.fingerprint()
+	return s
+}
+
+// addEHLO records the EHLO reply, the first line greets, the others list extensions.
+func (s *SMTPServer) addEHLO(lines []string, duration time.Duration) {
+	s.Tarpit = s.Tarpit || duration >= tarpitDelay
+	if len(lines) == 0 {
+		return
+	}
+	if fields := strings.Fields(lines[0]); s.Hostname == "" && len(fields) > 0 {
+		s.Hostname = fields[0]
+	}
+	for _, ext := range lines[1:] {
+		s.Capabilities = append(s.Capabilities, ext)
+		keyword, param, _ := strings.Cut(strings.ToUpper(ext), " ")
+		switch keyword {
+		case "SIZE":
+			s.MaxSize, _ = strconv.ParseInt(param, 10, 64)
+		case "SMTPUTF8":
+			s.SMTPUTF8 = true
+		case "PIPELINING":
+			s.Pipelining = true
+		}
+	}
+	s.fingerprint(lines[0])
+}
+
+func (s *SMTPServer) fingerprint(extra ...string) {
+	text := strings.Join(append([]string{s.Banner}, extra...), " ")
+	for _, f := range smtpFingerprints {
+		if !f.pattern.MatchString(text) {
+			continue
+		}
+		if s.Software == "" {
+			s.Software = f.software
+		}
+		if s.Provider == "" {
+			s.Provider = f.provider
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package emailverifier
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+type FreeProvider struct {
+	Name    string `json:"name"`
+	Country string `json:"country"`
+}
+
+var freeSyncDomains sync.Map
+
+func init() {
+	for d := range freeDomains.all() {
+		freeSyncDomains.Store(d, FreeProvider{})
+	}
+	for d, p := range freeProviders {
+		freeSyncDomains.Store(d, p)
+	}
+}
+
+func (p FreeProvider) String() string {
+	switch {
+	case p.Name == "":
+		return p.Country
+	case p.Country == "":
+		return p.Name
+	}
+	return fmt.Sprintf("%s (%s)", p.Name, p.Country)
+}
+
+// FreeProvider returns the provider registered for a free domain, or nil when the domain
+// is not free. The returned provider has empty fields when no metadata is known.
+func (v *Verifier) FreeProvider(domain string) *FreeProvider {
+	p, found := freeSyncDomains.Load(normalizeFreeDomain(domain))
+	if !found {
+		return nil
+	}
+	provider := p.(FreeProvider)
+	return &provider
+}
+
+// allFreeDomains ranges over the free domains, including the ones added at runtime.
+func allFreeDomains(yield func(string) bool) {
+	freeSyncDomains.Range(func(key, _ interface{}) bool {
+		return yield(key.(string))
+	})
+}
+
+func (v *Verifier) AddFreeDomains(domains []string) *Verifier {
+	for _, d := range domains {
+		d = normalizeFreeDomain(d)
+		if d == "" {
+			continue
+		}
+		if _, found := freeSyncDomains.Load(d); !found {
+			freeSyncDomains.Store(d, FreeProvider{})
+		}
+	}
+	return v
+}
+
+func (v *Verifier) AddFreeProvider(provider FreeProvider, domains []string) *Verifier {
+	for _, d := range domains {
+		if d = normalizeFreeDomain(d); d != "" {
+			freeSyncDomains.Store(d, provider)
+		}
+	}
+	return v
+}
+
+func (v *Verifier) RemoveFreeDomains(domains []string) *Verifier {
+	for _, d := range domains {
+		freeSyncDomains.Delete(normalizeFreeDomain(d))
+	}
+	return v
+}
+
+// FreeDomainsByCountry lists the free domains whose provider is registered for country.
+func (v *Verifier) FreeDomainsByCountry(country string) []string {
+	var domains []string
+	freeSyncDomains.Range(func(key, value interface{}) bool {
+		if strings.EqualFold(value.(FreeProvider).Country, country) {
+			domains = append(domains, key.(string))
+		}
+		return true
+	})
+	sort.Strings(domains)
+	return domains
+}
+
+func normalizeFreeDomain(domain string) string {
+	return domainToASCII(strings.ToLower(strings.TrimSpace(domain)))
+}
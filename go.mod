@@ -10,4 +10,4 @@ require (
 	golang.org/x/net v0.49.0
 )
 
-require golang.org/x/text v0.34.0 // indirect
+require golang.org/x/text v0.34.0
@@ -2,52 +2,259 @@ package emailverifier
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
-)
+)
+
+const (
+	GravatarHashSHA256 = "sha256"
+	GravatarHashMD5    = "md5"
+)
+
 type Gravatar struct {
-	HasGravatar bool   `json:"has_gravatar"`
-	GravatarUrl string `json:"gravatar_url"`
-}
+	HasGravatar bool             `json:"has_gravatar"`
+	GravatarUrl string           `json:"gravatar_url"`
+	Profile     *GravatarProfile `json:"profile,omitempty"`
+}
+
+type GravatarProfile struct {
+	DisplayName      string                    `json:"display_name"`
+	ProfileUrl       string                    `json:"profile_url"`
+	Location         string                    `json:"location,omitempty"`
+	JobTitle         string                    `json:"job_title,omitempty"`
+	Company          string                    `json:"company,omitempty"`
+	VerifiedAccounts []GravatarVerifiedAccount `json:"verified_accounts,omitempty"`
+}
+
+type GravatarVerifiedAccount struct {
+	Service string `json:"service_type"`
+	Label   string `json:"service_label"`
+	Url     string `json:"url"`
+}
+
+type gravatarConfig struct {
+	baseURL        string
+	profileBaseURL string
+	profileAPIKey  string
+	profileEnabled bool
+	hash           string
+	cacheTTL       time.Duration
+}
+
+type gravatarCacheEntry struct {
+	gravatar *Gravatar
+	expires  time.Time
+}
+
+var (
+	gravatarSyncCache sync.Map
+	gravatarCacheSize int64
+)
+
+func defaultGravatarConfig() gravatarConfig {
+	return gravatarConfig{
+		baseURL:        gravatarBaseUrl,
+		profileBaseURL: gravatarProfileBaseUrl,
+		hash:           GravatarHashSHA256,
+		cacheTTL:       gravatarCacheTTL,
+	}
+}
+
 func (v *Verifier) CheckGravatar(email string) (*Gravatar, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	hash, err := gravatarHash(v.gravatar.hash, email)
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := fmt.Sprintf("%s%s|%t", v.gravatar.baseURL, hash, v.gravatar.profileEnabled)
+	g, ok := loadCachedGravatar(cacheKey)
+	v.observe(CacheEvent{Cache: CacheGravatar, Hit: ok})
+	if ok {
+		return g, nil
+	}
+
+	ctx, cancel := v.http.withTimeout(context.Background())
 	defer cancel()
-	err, emailMd5 := getMD5Hash(strings.ToLower(strings.TrimSpace(email)))
+
+	gravatarUrl := v.gravatar.baseURL + hash + "?d=404"
+	start := time.Now()
+	found, err := v.gravatarExists(ctx, gravatarUrl)
+	v.observe(APICallEvent{API: "gravatar", Duration: time.Since(start), Err: err})
 	if err != nil {
 		return nil, err
 	}
-	gravatarUrl := gravatarBaseUrl + emailMd5 + "?d=404"
-	req, err := http.NewRequest("GET", gravatarUrl, nil)
+	ret := &Gravatar{}
+	if found {
+		ret.HasGravatar = true
+		ret.GravatarUrl = gravatarUrl
+		if v.gravatar.profileEnabled {
+			start = time.Now()
+			profile, err := v.gravatarProfile(ctx, hash)
+			v.observe(APICallEvent{API: "gravatar_profile", Duration: time.Since(start), Err: err})
+			if err != nil {
+				// keep the avatar result, but don't cache it so the profile is retried
+				return ret, nil
+			}
+			ret.Profile = profile
+		}
+	}
+
+	storeCachedGravatar(cacheKey, ret, v.gravatar.cacheTTL)
+	return ret, nil
+}
+
+// gravatarExists asks for the avatar with d=404 so a missing avatar never downloads the
+// default image. HEAD is used first; servers rejecting it get a GET whose body is discarded.
+// Only 404 means no avatar, other statuses are errors so that they are not cached.
+func (v *Verifier) gravatarExists(ctx context.Context, gravatarUrl string) (bool, error) {
+	status := 0
+	for _, method := range []string{http.MethodHead, http.MethodGet} {
+		req, err := v.http.newRequest(ctx, method, gravatarUrl, nil, "")
+		if err != nil {
+			return false, err
+		}
+		resp, err := v.http.httpClient().Do(req)
+		if err != nil {
+			return false, err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+
+		status = resp.StatusCode
+		switch status {
+		case http.StatusOK:
+			return true, nil
+		case http.StatusNotFound:
+			return false, nil
+		case http.StatusMethodNotAllowed:
+			continue
+		}
+		break
+	}
+	return false, fmt.Errorf("get gravatar with status_code: %d", status)
+}
+
+func (v *Verifier) gravatarProfile(ctx context.Context, hash string) (*GravatarProfile, error) {
+	req, err := v.http.newRequest(ctx, http.MethodGet, v.gravatar.profileBaseURL+hash, nil, "")
 	if err != nil {
 		return nil, err
 	}
-	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if v.gravatar.profileAPIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+v.gravatar.profileAPIKey)
+	}
+	resp, err := v.http.httpClient().Do(req)
 	if err != nil {
 		return nil, err
 	}
-
 	defer func() {
 		_ = resp.Body.Close()
 	}()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-	err, md5Body := getMD5Hash(string(body))
-	if err != nil {
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get gravatar profile with status_code: %d", resp.StatusCode)
+	}
+	var profile GravatarProfile
+	if err = json.NewDecoder(resp.Body).Decode(&profile); err != nil {
 		return nil, err
 	}
-	if md5Body == gravatarDefaultMd5 || resp.StatusCode != 200 {
-		return &Gravatar{
-			HasGravatar: false,
-			GravatarUrl: "",
-		}, nil
+	return &profile, nil
+}
+
+func gravatarHash(algorithm, email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	switch algorithm {
+	case GravatarHashMD5:
+		err, hash := getMD5Hash(email)
+		return hash, err
+	case GravatarHashSHA256, "":
+		return getSHA256Hash(email), nil
+	}
+	return "", fmt.Errorf("unsupported gravatar hash algorithm: %s", algorithm)
+}
+
+func loadCachedGravatar(key string) (*Gravatar, bool) {
+	v, ok := gravatarSyncCache.Load(key)
+	if !ok {
+		return nil, false
+	}
+	entry := v.(gravatarCacheEntry)
+	if time.Now().After(entry.expires) {
+		if gravatarSyncCache.CompareAndDelete(key, v) {
+			atomic.AddInt64(&gravatarCacheSize, -1)
+		}
+		return nil, false
+	}
+	g := *entry.gravatar
+	return &g, true
+}
+
+func storeCachedGravatar(key string, g *Gravatar, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	if atomic.LoadInt64(&gravatarCacheSize) >= gravatarCacheMaxSize {
+		evictExpiredGravatars()
+	}
+	cached := *g
+	entry := gravatarCacheEntry{gravatar: &cached, expires: time.Now().Add(ttl)}
+	if _, loaded := gravatarSyncCache.Swap(key, entry); !loaded {
+		atomic.AddInt64(&gravatarCacheSize, 1)
+	}
+}
+
+// evictExpiredGravatars drops expired entries, and everything if the cache is still full.
+func evictExpiredGravatars() {
+	now := time.Now()
+	gravatarSyncCache.Range(func(key, value interface{}) bool {
+		if now.After(value.(gravatarCacheEntry).expires) && gravatarSyncCache.CompareAndDelete(key, value) {
+			atomic.AddInt64(&gravatarCacheSize, -1)
+		}
+		return true
+	})
+	if atomic.LoadInt64(&gravatarCacheSize) >= gravatarCacheMaxSize {
+		gravatarSyncCache.Range(func(key, value interface{}) bool {
+			if gravatarSyncCache.CompareAndDelete(key, value) {
+				atomic.AddInt64(&gravatarCacheSize, -1)
+			}
+			return true
+		})
+	}
+}
+
+func (v *Verifier) GravatarBaseURL(baseURL string) *Verifier {
+	v.gravatar.baseURL = baseURL
+	return v
+}
+
+func (v *Verifier) GravatarHash(algorithm string) *Verifier {
+	v.gravatar.hash = algorithm
+	return v
+}
+
+func (v *Verifier) GravatarCacheTTL(ttl time.Duration) *Verifier {
+	v.gravatar.cacheTTL = ttl
+	return v
+}
+
+func (v *Verifier) EnableGravatarProfile(profileBaseURL, apiKey string) *Verifier {
+	v.gravatar.profileEnabled = true
+	if profileBaseURL != "" {
+		v.gravatar.profileBaseURL = profileBaseURL
 	}
-	return &Gravatar{
-		HasGravatar: true,
-		GravatarUrl: gravatarUrl,
-	}, nil
+	v.gravatar.profileAPIKey = apiKey
+	return v
+}
+
+func (v *Verifier) DisableGravatarProfile() *Verifier {
+	v.gravatar.profileEnabled = false
+	return v
 }
@@ -6,17 +6,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"time"
-)
-func updateDisposableDomains(source string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+)
+
+func updateDisposableDomains(source string, hc *httpConfig) error {
+	ctx, cancel := hc.withTimeout(context.Background())
 	defer cancel()
-	req, err := http.NewRequest("GET", source, nil)
+	req, err := hc.newRequest(ctx, http.MethodGet, source, nil, "")
 	if err != nil {
 		return err
 	}
 
-	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	resp, err := hc.httpClient().Do(req)
 	if err != nil {
 		return err
 	}
@@ -41,21 +41,6 @@ func updateDisposableDomains(source string) error {
 		return err
 	}
 
-	newDomains := make(map[string]struct{})
-	for _, v := range domains {
-		newDomains[v] = struct{}{}
-	}
-	disposableSyncDomains.Range(func(key, value interface{}) bool {
-		if _, exists := newDomains[key.(string)]; !exists {
-			disposableSyncDomains.Delete(key)
-		}
-		return true
-	})
-	for _, d := range domains {
-		disposableSyncDomains.Store(d, struct{}{})
-	}
-	for d := range additionalDisposableDomains {
-		disposableSyncDomains.Store(d, struct{}{})
-	}
+	disposableDomainList.Store(newMetadataListFromEntries(domains))
 	return nil
 }
@@ -0,0 +1,67 @@
+package emailverifier
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// httpConfig is shared by every HTTP-backed check of a Verifier: gravatar lookups,
+// disposable list updates and the API verifiers.
+type httpConfig struct {
+	client    *http.Client
+	userAgent string
+	timeout   time.Duration
+}
+
+func (c *httpConfig) httpClient() *http.Client {
+	if c == nil || c.client == nil {
+		return http.DefaultClient
+	}
+	return c.client
+}
+
+func (c *httpConfig) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c == nil || c.timeout <= 0 {
+		return context.WithTimeout(ctx, defaultHTTPTimeout)
+	}
+	return context.WithTimeout(ctx, c.timeout)
+}
+
+// newRequest builds a request carrying the configured user agent, or defaultUserAgent
+// when none is configured.
+func (c *httpConfig) newRequest(ctx context.Context, method, url string, body io.Reader, defaultUserAgent string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	ua := defaultUserAgent
+	if c != nil && c.userAgent != "" {
+		ua = c.userAgent
+	}
+	if ua != "" {
+		req.Header.Set("User-Agent", ua)
+	}
+	return req, nil
+}
+
+func (v *Verifier) HTTPClient(client *http.Client) *Verifier {
+	v.http.client = client
+	return v
+}
+
+func (v *Verifier) HTTPTransport(transport http.RoundTripper) *Verifier {
+	v.http.client = &http.Client{Transport: transport}
+	return v
+}
+
+func (v *Verifier) UserAgent(userAgent string) *Verifier {
+	v.http.userAgent = userAgent
+	return v
+}
+
+func (v *Verifier) HTTPTimeout(timeout time.Duration) *Verifier {
+	v.http.timeout = timeout
+	return v
+}
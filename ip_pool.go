@@ -0,0 +1,206 @@
+package emailverifier
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+const (
+	defaultIPMaxBlocks     = 3
+	defaultIPQuarantineFor = 30 * time.Minute
+)
+
+var ErrNoSourceIPAvailable = errors.New("no source IP available")
+
+type IPStats struct {
+	IP          string               `json:"ip"`
+	Uses        uint64               `json:"uses"`
+	Quarantined map[string]time.Time `json:"quarantined,omitempty"`
+	Blocks      map[string]int       `json:"blocks,omitempty"`
+}
+
+type ipProviderHealth struct {
+	blocks           int
+	quarantinedUntil time.Time
+}
+
+type sourceIP struct {
+	ip        net.IP
+	uses      uint64
+	providers map[string]*ipProviderHealth
+}
+
+// IPPool rotates the source IPs used for SMTP connections. Block responses are tracked
+// per IP and per provider, so an IP blocked by one provider keeps serving the others.
+// A pool is safe for concurrent use and meant to be shared by verifiers.
+type IPPool struct {
+	mu            sync.Mutex
+	ips           []*sourceIP
+	next          int
+	maxBlocks     int
+	quarantineFor time.Duration
+}
+
+func NewIPPool(addrs []string) (*IPPool, error) {
+	if len(addrs) == 0 {
+		return nil, errors.New("ip pool needs at least one address")
+	}
+	p := &IPPool{
+		maxBlocks:     defaultIPMaxBlocks,
+		quarantineFor: defaultIPQuarantineFor,
+	}
+	for _, addr := range addrs {
+		ip := parseLocalIP(addr)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid source ip %q", addr)
+		}
+		p.ips = append(p.ips, &sourceIP{ip: ip, providers: map[string]*ipProviderHealth{}})
+	}
+	return p, nil
+}
+
+// QuarantineAfter stops using an IP for a provider during quarantineFor once the
+// provider blocked it maxBlocks times in a row.
+func (p *IPPool) QuarantineAfter(maxBlocks int, quarantineFor time.Duration) *IPPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxBlocks = maxBlocks
+	p.quarantineFor = quarantineFor
+	return p
+}
+
+func (p *IPPool) Stats() []IPStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	stats := make([]IPStats, 0, len(p.ips))
+	for _, s := range p.ips {
+		st := IPStats{IP: s.ip.String(), Uses: s.uses}
+		for provider, h := range s.providers {
+			if h.blocks > 0 {
+				if st.Blocks == nil {
+					st.Blocks = map[string]int{}
+				}
+				st.Blocks[provider] = h.blocks
+			}
+			if now.Before(h.quarantinedUntil) {
+				if st.Quarantined == nil {
+					st.Quarantined = map[string]time.Time{}
+				}
+				st.Quarantined[provider] = h.quarantinedUntil
+			}
+		}
+		stats = append(stats, st)
+	}
+	return stats
+}
+
+func (p *IPPool) acquire(provider string) (net.IP, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(p.ips); i++ {
+		s := p.ips[(p.next+i)%len(p.ips)]
+		if h, ok := s.providers[provider]; ok && now.Before(h.quarantinedUntil) {
+			continue
+		}
+		p.next = (p.next + i + 1) % len(p.ips)
+		s.uses++
+		return s.ip, nil
+	}
+	return nil, ErrNoSourceIPAvailable
+}
+
+func (p *IPPool) report(ip net.IP, provider string, blocked bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, s := range p.ips {
+		if !s.ip.Equal(ip) {
+			continue
+		}
+		h, ok := s.providers[provider]
+		if !ok {
+			if !blocked {
+				return
+			}
+			h = &ipProviderHealth{}
+			s.providers[provider] = h
+		}
+		if !blocked {
+			h.blocks = 0
+			return
+		}
+		h.blocks++
+		if p.maxBlocks > 0 && h.blocks >= p.maxBlocks {
+			h.quarantinedUntil = time.Now().Add(p.quarantineFor)
+			h.blocks = 0
+		}
+		return
+	}
+}
+
+func (v *Verifier) IPPool(pool *IPPool) *Verifier {
+	v.ipPool = pool
+	return v
+}
+
+// acquireSourceIP returns the local IP to dial from, nil to let the OS choose, and a
+// func reporting whether the provider blocked it.
+func (v *Verifier) acquireSourceIP(provider string) (net.IP, func(bool), error) {
+	if v.ipPool != nil {
+		ip, err := v.ipPool.acquire(provider)
+		if err != nil {
+			return nil, nil, err
+		}
+		return ip, func(blocked bool) { v.ipPool.report(ip, provider, blocked) }, nil
+	}
+	return parseLocalIP(v.localAddr), func(bool) {}, nil
+}
+
+// parseLocalIP accepts a bare IPv4/IPv6 address, optionally in brackets or with a port.
+func parseLocalIP(addr string) net.IP {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return nil
+	}
+	if ip := net.ParseIP(strings.Trim(addr, "[]")); ip != nil {
+		return ip
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return net.ParseIP(host)
+	}
+	return nil
+}
+
+// mxProvider groups MX hosts by their registered domain, e.g. "google.com" for
+// "gmail-smtp-in.l.google.com" or "example.co.uk" for "mx1.example.co.uk".
+func mxProvider(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if provider, err := publicsuffix.EffectiveTLDPlusOne(host); err == nil {
+		return provider
+	}
+	return host
+}
+
+// isIPBlockSignal reports responses that point at the sending IP rather than the
+// mailbox. 554 is only meaningful before RCPT, where it rejects the connection itself.
+func isIPBlockSignal(e *LookupError, beforeRcpt bool) bool {
+	if e == nil {
+		return false
+	}
+	switch e.Message {
+	case ErrBlocked, ErrTryAgainLater:
+		return true
+	case ErrNotAllowed:
+		return beforeRcpt
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package emailverifier
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"iter"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// metadataList is a read-only sorted set of strings. Entries are kept in a single
+// newline separated string indexed by offsets, which is far smaller than a map and
+// is only decompressed on first use.
+type metadataList struct {
+	once       sync.Once
+	compressed []byte
+	entries    string
+	offsets    []uint32
+}
+
+func newMetadataList(compressed []byte) *metadataList {
+	return &metadataList{compressed: compressed}
+}
+
+func newMetadataListFromEntries(entries []string) *metadataList {
+	sorted := append([]string(nil), entries...)
+	sort.Strings(sorted)
+	var buf strings.Builder
+	var last string
+	for i, e := range sorted {
+		if e == "" || (i > 0 && e == last) {
+			continue
+		}
+		buf.WriteString(e)
+		buf.WriteByte('\n')
+		last = e
+	}
+	l := &metadataList{}
+	l.once.Do(func() { l.index(buf.String()) })
+	return l
+}
+
+func (l *metadataList) load() {
+	l.once.Do(func() {
+		r, err := gzip.NewReader(bytes.NewReader(l.compressed))
+		if err != nil {
+			panic(fmt.Sprintf("emailverifier: invalid metadata: %v", err))
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			panic(fmt.Sprintf("emailverifier: invalid metadata: %v", err))
+		}
+		l.compressed = nil
+		l.index(string(data))
+	})
+}
+
+func (l *metadataList) index(entries string) {
+	if entries != "" && !strings.HasSuffix(entries, "\n") {
+		entries += "\n"
+	}
+	l.entries = entries
+	l.offsets = make([]uint32, 0, strings.Count(entries, "\n")+1)
+	for start := 0; start < len(entries); {
+		l.offsets = append(l.offsets, uint32(start))
+		start += strings.IndexByte(entries[start:], '\n') + 1
+	}
+	l.offsets = append(l.offsets, uint32(len(entries)))
+}
+
+// entry returns the i-th entry without its trailing newline.
+func (l *metadataList) entry(i int) string {
+	return l.entries[l.offsets[i] : l.offsets[i+1]-1]
+}
+
+func (l *metadataList) len() int {
+	l.load()
+	return len(l.offsets) - 1
+}
+
+func (l *metadataList) contains(s string) bool {
+	n := l.len()
+	i := sort.Search(n, func(i int) bool { return l.entry(i) >= s })
+	return i < n && l.entry(i) == s
+}
+
+func (l *metadataList) all() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for i, n := 0, l.len(); i < n; i++ {
+			if !yield(l.entry(i)) {
+				return
+			}
+		}
+	}
+}
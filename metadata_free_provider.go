@@ -0,0 +1,95 @@
+package emailverifier
+
+// provider name and country for well-known free email domains
+var freeProviders = map[string]FreeProvider{
+	"gmail.com":       {Name: "Gmail", Country: "US"},
+	"googlemail.com":  {Name: "Gmail", Country: "US"},
+	"yahoo.com":       {Name: "Yahoo", Country: "US"},
+	"ymail.com":       {Name: "Yahoo", Country: "US"},
+	"yahoo.co.uk":     {Name: "Yahoo", Country: "GB"},
+	"yahoo.fr":        {Name: "Yahoo", Country: "FR"},
+	"yahoo.de":        {Name: "Yahoo", Country: "DE"},
+	"yahoo.co.jp":     {Name: "Yahoo Japan", Country: "JP"},
+	"hotmail.com":     {Name: "Outlook", Country: "US"},
+	"outlook.com":     {Name: "Outlook", Country: "US"},
+	"live.com":        {Name: "Outlook", Country: "US"},
+	"msn.com":         {Name: "Outlook", Country: "US"},
+	"aol.com":         {Name: "AOL", Country: "US"},
+	"icloud.com":      {Name: "iCloud", Country: "US"},
+	"me.com":          {Name: "iCloud", Country: "US"},
+	"mac.com":         {Name: "iCloud", Country: "US"},
+	"mail.com":        {Name: "Mail.com", Country: "US"},
+	"zoho.com":        {Name: "Zoho", Country: "IN"},
+	"fastmail.com":    {Name: "Fastmail", Country: "AU"},
+	"hushmail.com":    {Name: "Hushmail", Country: "CA"},
+	"protonmail.com":  {Name: "Proton", Country: "CH"},
+	"proton.me":       {Name: "Proton", Country: "CH"},
+	"tutanota.com":    {Name: "Tuta", Country: "DE"},
+	"comcast.net":     {Name: "Xfinity", Country: "US"},
+	"verizon.net":     {Name: "Verizon", Country: "US"},
+	"att.net":         {Name: "AT&T", Country: "US"},
+	"sbcglobal.net":   {Name: "AT&T", Country: "US"},
+	"cox.net":         {Name: "Cox", Country: "US"},
+	"shaw.ca":         {Name: "Shaw", Country: "CA"},
+	"rogers.com":      {Name: "Rogers", Country: "CA"},
+	"sympatico.ca":    {Name: "Bell", Country: "CA"},
+	"gmx.de":          {Name: "GMX", Country: "DE"},
+	"gmx.net":         {Name: "GMX", Country: "DE"},
+	"gmx.at":          {Name: "GMX", Country: "AT"},
+	"gmx.ch":          {Name: "GMX", Country: "CH"},
+	"gmx.com":         {Name: "GMX", Country: "DE"},
+	"web.de":          {Name: "WEB.DE", Country: "DE"},
+	"t-online.de":     {Name: "T-Online", Country: "DE"},
+	"freenet.de":      {Name: "freenet", Country: "DE"},
+	"posteo.de":       {Name: "Posteo", Country: "DE"},
+	"mail.de":         {Name: "mail.de", Country: "DE"},
+	"arcor.de":        {Name: "Arcor", Country: "DE"},
+	"bluewin.ch":      {Name: "Bluewin", Country: "CH"},
+	"orange.fr":       {Name: "Orange", Country: "FR"},
+	"wanadoo.fr":      {Name: "Orange", Country: "FR"},
+	"free.fr":         {Name: "Free", Country: "FR"},
+	"laposte.net":     {Name: "La Poste", Country: "FR"},
+	"sfr.fr":          {Name: "SFR", Country: "FR"},
+	"libero.it":       {Name: "Libero", Country: "IT"},
+	"virgilio.it":     {Name: "Virgilio", Country: "IT"},
+	"tiscali.it":      {Name: "Tiscali", Country: "IT"},
+	"alice.it":        {Name: "TIM", Country: "IT"},
+	"telenet.be":      {Name: "Telenet", Country: "BE"},
+	"skynet.be":       {Name: "Proximus", Country: "BE"},
+	"ziggo.nl":        {Name: "Ziggo", Country: "NL"},
+	"kpnmail.nl":      {Name: "KPN", Country: "NL"},
+	"planet.nl":       {Name: "KPN", Country: "NL"},
+	"btinternet.com":  {Name: "BT", Country: "GB"},
+	"sky.com":         {Name: "Sky", Country: "GB"},
+	"virginmedia.com": {Name: "Virgin Media", Country: "GB"},
+	"seznam.cz":       {Name: "Seznam", Country: "CZ"},
+	"wp.pl":           {Name: "WP", Country: "PL"},
+	"o2.pl":           {Name: "WP", Country: "PL"},
+	"interia.pl":      {Name: "Interia", Country: "PL"},
+	"onet.pl":         {Name: "Onet", Country: "PL"},
+	"mail.ru":         {Name: "Mail.ru", Country: "RU"},
+	"inbox.ru":        {Name: "Mail.ru", Country: "RU"},
+	"list.ru":         {Name: "Mail.ru", Country: "RU"},
+	"bk.ru":           {Name: "Mail.ru", Country: "RU"},
+	"yandex.ru":       {Name: "Yandex", Country: "RU"},
+	"yandex.com":      {Name: "Yandex", Country: "RU"},
+	"rambler.ru":      {Name: "Rambler", Country: "RU"},
+	"qq.com":          {Name: "QQ Mail", Country: "CN"},
+	"163.com":         {Name: "NetEase", Country: "CN"},
+	"126.com":         {Name: "NetEase", Country: "CN"},
+	"yeah.net":        {Name: "NetEase", Country: "CN"},
+	"sina.com":        {Name: "Sina", Country: "CN"},
+	"sohu.com":        {Name: "Sohu", Country: "CN"},
+	"139.com":         {Name: "China Mobile", Country: "CN"},
+	"naver.com":       {Name: "Naver", Country: "KR"},
+	"daum.net":        {Name: "Daum", Country: "KR"},
+	"hanmail.net":     {Name: "Daum", Country: "KR"},
+	"nifty.com":       {Name: "@nifty", Country: "JP"},
+	"rediffmail.com":  {Name: "Rediffmail", Country: "IN"},
+	"uol.com.br":      {Name: "UOL", Country: "BR"},
+	"bol.com.br":      {Name: "BOL", Country: "BR"},
+	"terra.com.br":    {Name: "Terra", Country: "BR"},
+	"bigpond.com":     {Name: "Telstra", Country: "AU"},
+	"optusnet.com.au": {Name: "Optus", Country: "AU"},
+	"xtra.co.nz":      {Name: "Spark", Country: "NZ"},
+}
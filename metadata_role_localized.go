@@ -0,0 +1,174 @@
+package emailverifier
+
+// role keywords per language, mapped to the role category they belong to
+var localizedRoleAccounts = map[string]map[string]string{
+	"en": {
+		"abuse":             RoleCategoryAdmin,
+		"admin":             RoleCategoryAdmin,
+		"administrator":     RoleCategoryAdmin,
+		"hostmaster":        RoleCategoryAdmin,
+		"postmaster":        RoleCategoryAdmin,
+		"webmaster":         RoleCategoryAdmin,
+		"root":              RoleCategoryAdmin,
+		"sysadmin":          RoleCategoryAdmin,
+		"security":          RoleCategoryAdmin,
+		"it":                RoleCategoryAdmin,
+		"noc":               RoleCategoryAdmin,
+		"dns":               RoleCategoryAdmin,
+		"billing":           RoleCategoryBilling,
+		"accounting":        RoleCategoryBilling,
+		"accounts":          RoleCategoryBilling,
+		"accountspayable":   RoleCategoryBilling,
+		"finance":           RoleCategoryBilling,
+		"invoice":           RoleCategoryBilling,
+		"invoices":          RoleCategoryBilling,
+		"payments":          RoleCategoryBilling,
+		"payroll":           RoleCategoryBilling,
+		"careers":           RoleCategoryHR,
+		"hiring":            RoleCategoryHR,
+		"hr":                RoleCategoryHR,
+		"jobs":              RoleCategoryHR,
+		"recruiting":        RoleCategoryHR,
+		"recruitment":       RoleCategoryHR,
+		"humanresources":    RoleCategoryHR,
+		"contact":           RoleCategoryGeneral,
+		"contactus":         RoleCategoryGeneral,
+		"enquiries":         RoleCategoryGeneral,
+		"hello":             RoleCategoryGeneral,
+		"info":              RoleCategoryGeneral,
+		"inquiries":         RoleCategoryGeneral,
+		"office":            RoleCategoryGeneral,
+		"reception":         RoleCategoryGeneral,
+		"team":              RoleCategoryGeneral,
+		"marketing":         RoleCategoryMarketing,
+		"media":             RoleCategoryMarketing,
+		"newsletter":        RoleCategoryMarketing,
+		"press":             RoleCategoryMarketing,
+		"pr":                RoleCategoryMarketing,
+		"bounce":            RoleCategoryNoReply,
+		"bounces":           RoleCategoryNoReply,
+		"donotreply":        RoleCategoryNoReply,
+		"mailerdaemon":      RoleCategoryNoReply,
+		"noreply":           RoleCategoryNoReply,
+		"noresponse":        RoleCategoryNoReply,
+		"notifications":     RoleCategoryNoReply,
+		"notify":            RoleCategoryNoReply,
+		"unsubscribe":       RoleCategoryNoReply,
+		"bizdev":            RoleCategorySales,
+		"orders":            RoleCategorySales,
+		"presales":          RoleCategorySales,
+		"quotes":            RoleCategorySales,
+		"sales":             RoleCategorySales,
+		"care":              RoleCategorySupport,
+		"customercare":      RoleCategorySupport,
+		"customerservice":   RoleCategorySupport,
+		"customersupport":   RoleCategorySupport,
+		"help":              RoleCategorySupport,
+		"helpdesk":          RoleCategorySupport,
+		"service":           RoleCategorySupport,
+		"servicedesk":       RoleCategorySupport,
+		"support":           RoleCategorySupport,
+		"techsupport":       RoleCategorySupport,
+		"tickets":           RoleCategorySupport,
+		"customersuccess":   RoleCategorySupport,
+		"investorrelations": RoleCategoryGeneral,
+	},
+	"de": {
+		"buchhaltung":   RoleCategoryBilling,
+		"rechnung":      RoleCategoryBilling,
+		"bewerbung":     RoleCategoryHR,
+		"karriere":      RoleCategoryHR,
+		"kontakt":       RoleCategoryGeneral,
+		"zentrale":      RoleCategoryGeneral,
+		"sekretariat":   RoleCategoryGeneral,
+		"verwaltung":    RoleCategoryAdmin,
+		"presse":        RoleCategoryMarketing,
+		"keineantwort":  RoleCategoryNoReply,
+		"bestellung":    RoleCategorySales,
+		"verkauf":       RoleCategorySales,
+		"vertrieb":      RoleCategorySales,
+		"kundendienst":  RoleCategorySupport,
+		"kundenservice": RoleCategorySupport,
+		"hilfe":         RoleCategorySupport,
+		"technik":       RoleCategorySupport,
+	},
+	"es": {
+		"administracion":    RoleCategoryAdmin,
+		"facturacion":       RoleCategoryBilling,
+		"contabilidad":      RoleCategoryBilling,
+		"rrhh":              RoleCategoryHR,
+		"empleo":            RoleCategoryHR,
+		"contacto":          RoleCategoryGeneral,
+		"informacion":       RoleCategoryGeneral,
+		"hola":              RoleCategoryGeneral,
+		"oficina":           RoleCategoryGeneral,
+		"prensa":            RoleCategoryMarketing,
+		"noresponder":       RoleCategoryNoReply,
+		"ventas":            RoleCategorySales,
+		"pedidos":           RoleCategorySales,
+		"comercial":         RoleCategorySales,
+		"soporte":           RoleCategorySupport,
+		"ayuda":             RoleCategorySupport,
+		"atencionalcliente": RoleCategorySupport,
+	},
+	"fr": {
+		"administratif": RoleCategoryAdmin,
+		"comptabilite":  RoleCategoryBilling,
+		"facturation":   RoleCategoryBilling,
+		"recrutement":   RoleCategoryHR,
+		"emploi":        RoleCategoryHR,
+		"bonjour":       RoleCategoryGeneral,
+		"accueil":       RoleCategoryGeneral,
+		"secretariat":   RoleCategoryGeneral,
+		"presse":        RoleCategoryMarketing,
+		"nepasrepondre": RoleCategoryNoReply,
+		"ventes":        RoleCategorySales,
+		"commandes":     RoleCategorySales,
+		"commercial":    RoleCategorySales,
+		"serviceclient": RoleCategorySupport,
+		"assistance":    RoleCategorySupport,
+		"aide":          RoleCategorySupport,
+	},
+	"it": {
+		"amministrazione": RoleCategoryAdmin,
+		"contabilita":     RoleCategoryBilling,
+		"fatturazione":    RoleCategoryBilling,
+		"lavoro":          RoleCategoryHR,
+		"contatti":        RoleCategoryGeneral,
+		"informazioni":    RoleCategoryGeneral,
+		"segreteria":      RoleCategoryGeneral,
+		"stampa":          RoleCategoryMarketing,
+		"nonrispondere":   RoleCategoryNoReply,
+		"vendite":         RoleCategorySales,
+		"ordini":          RoleCategorySales,
+		"commerciale":     RoleCategorySales,
+		"assistenza":      RoleCategorySupport,
+		"supporto":        RoleCategorySupport,
+	},
+	"nl": {
+		"administratie":  RoleCategoryAdmin,
+		"boekhouding":    RoleCategoryBilling,
+		"facturen":       RoleCategoryBilling,
+		"vacatures":      RoleCategoryHR,
+		"algemeen":       RoleCategoryGeneral,
+		"secretariaat":   RoleCategoryGeneral,
+		"verkoop":        RoleCategorySales,
+		"bestellingen":   RoleCategorySales,
+		"klantenservice": RoleCategorySupport,
+		"ondersteuning":  RoleCategorySupport,
+	},
+	"pt": {
+		"administracao": RoleCategoryAdmin,
+		"financeiro":    RoleCategoryBilling,
+		"faturamento":   RoleCategoryBilling,
+		"rh":            RoleCategoryHR,
+		"contato":       RoleCategoryGeneral,
+		"contacto":      RoleCategoryGeneral,
+		"imprensa":      RoleCategoryMarketing,
+		"naoresponda":   RoleCategoryNoReply,
+		"vendas":        RoleCategorySales,
+		"pedidos":       RoleCategorySales,
+		"atendimento":   RoleCategorySupport,
+		"suporte":       RoleCategorySupport,
+	},
+}
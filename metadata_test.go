@@ -0,0 +1,62 @@
+package emailverifier
+
+import (
+	"slices"
+	"testing"
+)
+
+// The map benchmarks build the map[string]bool the metadata lists replaced, to compare
+// first-use cost and lookups with it.
+
+func disposableEntries() []string {
+	return slices.Collect(newMetadataList(disposableDomainsData).all())
+}
+
+func BenchmarkMetadataListLoad(b *testing.B) {
+	b.ReportAllocs()
+	for b.Loop() {
+		newMetadataList(disposableDomainsData).load()
+	}
+}
+
+func BenchmarkMapBuild(b *testing.B) {
+	entries := disposableEntries()
+	b.ReportAllocs()
+	for b.Loop() {
+		m := make(map[string]bool)
+		for _, e := range entries {
+			m[e] = true
+		}
+	}
+}
+
+func BenchmarkMetadataListContains(b *testing.B) {
+	l := newMetadataList(disposableDomainsData)
+	l.load()
+	b.ReportAllocs()
+	for b.Loop() {
+		l.contains("mailinator.com")
+		l.contains("gmail.com")
+	}
+}
+
+func BenchmarkMapContains(b *testing.B) {
+	m := make(map[string]bool)
+	for _, e := range disposableEntries() {
+		m[e] = true
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = m["mailinator.com"]
+		_ = m["gmail.com"]
+	}
+}
+
+func BenchmarkIsDisposable(b *testing.B) {
+	v := NewVerifier()
+	b.ReportAllocs()
+	for b.Loop() {
+		v.IsDisposable("mailinator.com")
+		v.IsDisposable("gmail.com")
+	}
+}
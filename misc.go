@@ -1,21 +1,33 @@
 package emailverifier
 
 import (
-	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 var (
 	disposableSyncDomains sync.Map
-)
+	disposableDomainList  atomic.Pointer[metadataList]
+)
+
+func init() {
+	disposableDomainList.Store(disposableDomains)
+}
+
 func (v *Verifier) IsRoleAccount(username string) bool {
-	return roleAccounts[strings.ToLower(username)]
-}
+	return v.RoleCategory(username) != ""
+}
+
 func (v *Verifier) IsFreeDomain(domain string) bool {
-	return freeDomains[domain]
-}
+	_, found := freeSyncDomains.Load(normalizeFreeDomain(domain))
+	return found
+}
+
 func (v *Verifier) IsDisposable(domain string) bool {
 	domain = domainToASCII(domain)
+	if disposableDomainList.Load().contains(domain) {
+		return true
+	}
 	_, found := disposableSyncDomains.Load(domain)
 	return found
 }
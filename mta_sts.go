@@ -0,0 +1,145 @@
+package emailverifier
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	MTASTSModeEnforce = "enforce"
+	MTASTSModeTesting = "testing"
+	MTASTSModeNone    = "none"
+
+	// RFC 8461 caps the policy file at 64KB
+	mtaSTSMaxPolicySize = 64 * 1024
+)
+
+type MTASTS struct {
+	ID     string   `json:"id"`
+	Mode   string   `json:"mode"`
+	MX     []string `json:"mx"`
+	MaxAge int      `json:"max_age"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// matches reports whether host is allowed by one of the policy mx patterns. A leading
+// "*." matches exactly one label.
+func (p *MTASTS) matches(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, pattern := range p.MX {
+		pattern = strings.ToLower(pattern)
+		if strings.HasPrefix(pattern, "*.") {
+			if i := strings.IndexByte(host, '.'); i > 0 && host[i+1:] == pattern[2:] {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupMTASTS returns nil when the domain publishes no _mta-sts record. Failures are
+// kept in Error, and the error of the _mta-sts lookup itself is also returned.
+func lookupMTASTS(ctx context.Context, domain string, hc *httpConfig) (*MTASTS, error) {
+	txt, err := lookupTaggedTXT(ctx, "_mta-sts."+domain, "v=STSv1")
+	if err != nil {
+		return &MTASTS{Error: err.Error()}, err
+	}
+	if txt == "" {
+		return nil, nil
+	}
+	policy := &MTASTS{ID: parseTagList(txt)["id"]}
+	if err := fetchMTASTSPolicy(ctx, domain, hc, policy); err != nil {
+		policy.Error = err.Error()
+	}
+	return policy, nil
+}
+
+func fetchMTASTSPolicy(ctx context.Context, domain string, hc *httpConfig, policy *MTASTS) error {
+	ctx, cancel := hc.withTimeout(ctx)
+	defer cancel()
+
+	req, err := hc.newRequest(ctx, http.MethodGet, "https://mta-sts."+domain+"/.well-known/mta-sts.txt", nil, "")
+	if err != nil {
+		return err
+	}
+	// policy fetches must not follow redirects
+	client := *hc.httpClient()
+	client.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("mta-sts policy: %s", resp.Status)
+	}
+
+	scanner := bufio.NewScanner(io.LimitReader(resp.Body, mtaSTSMaxPolicySize))
+	var version string
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "version":
+			version = value
+		case "mode":
+			policy.Mode = value
+		case "mx":
+			policy.MX = append(policy.MX, value)
+		case "max_age":
+			policy.MaxAge, _ = strconv.Atoi(value)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	if version != "STSv1" {
+		return errors.New("mta-sts policy: unsupported version")
+	}
+	return nil
+}
+
+// lookupTaggedTXT returns the TXT record of name starting with prefix, or "" when none
+// is published.
+func lookupTaggedTXT(ctx context.Context, name, prefix string) (string, error) {
+	records, err := net.DefaultResolver.LookupTXT(ctx, name)
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, r := range records {
+		if len(r) >= len(prefix) && strings.EqualFold(r[:len(prefix)], prefix) {
+			return r, nil
+		}
+	}
+	return "", nil
+}
+
+// parseTagList parses "k=v; k2=v2" records as used by DMARC, DKIM, MTA-STS and TLS-RPT.
+func parseTagList(record string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(record, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		tags[strings.ToLower(strings.TrimSpace(key))] = strings.TrimSpace(value)
+	}
+	return tags
+}
@@ -1,6 +1,9 @@
 package emailverifier
 
-import "net"
+import (
+	"net"
+	"time"
+)
 
 type Mx struct {
 	HasMXRecord bool
@@ -9,7 +12,9 @@ type Mx struct {
 
 func (v *Verifier) CheckMX(domain string) (*Mx, error) {
 	domain = domainToASCII(domain)
+	start := time.Now()
 	mx, err := net.LookupMX(domain)
+	v.observe(DNSLookupEvent{Type: "MX", Name: domain, Duration: time.Since(start), Err: err})
 	if err != nil && len(mx) == 0 {
 		return nil, err
 	}
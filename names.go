@@ -0,0 +1,169 @@
+package emailverifier
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	NamePatternFirstLast         = "first.last"
+	NamePatternInitialLast       = "f.last"
+	NamePatternFirstInitial      = "first.l"
+	NamePatternLastFirst         = "last_first"
+	NamePatternFirstLastJoined   = "firstlast"
+	NamePatternInitialLastJoined = "flast"
+	NamePatternLastFirstJoined   = "lastfirst"
+	NamePatternFirst             = "first"
+	NamePatternLast              = "last"
+
+	minNameLen = 2
+)
+
+// InferredName is a guess at the person behind a local part. Initials are set instead
+// of the names when the local part only holds the first letter.
+type InferredName struct {
+	FirstName    string  `json:"first_name"`
+	LastName     string  `json:"last_name"`
+	FirstInitial string  `json:"first_initial,omitempty"`
+	LastInitial  string  `json:"last_initial,omitempty"`
+	Pattern      string  `json:"pattern"`
+	Confidence   float64 `json:"confidence"`
+}
+
+func (v *Verifier) EnableNameInference() *Verifier {
+	v.nameInferenceEnabled = true
+	return v
+}
+
+func (v *Verifier) DisableNameInference() *Verifier {
+	v.nameInferenceEnabled = false
+	return v
+}
+
+// InferName matches username against common conventions such as first.last, f.last,
+// firstlast and last_first using the embedded given name and surname lists. It returns
+// nil when no convention fits.
+func (v *Verifier) InferName(username string) *InferredName {
+	local := strings.ToLower(username)
+	if i := strings.IndexByte(local, '+'); i > 0 {
+		local = local[:i]
+	}
+	local = strings.TrimRight(local, "0123456789")
+
+	tokens := strings.FieldsFunc(local, isRoleSeparator)
+	for _, t := range tokens {
+		if !isLetters(t) {
+			return nil
+		}
+	}
+
+	var n *InferredName
+	switch len(tokens) {
+	case 0:
+		return nil
+	case 1:
+		n = inferJoinedName(tokens[0])
+	case 2:
+		n = inferSeparatedName(tokens[0], tokens[1])
+	default:
+		// middle names are dropped, first and last token are the ones that count
+		if n = inferSeparatedName(tokens[0], tokens[len(tokens)-1]); n != nil {
+			n.Confidence -= 0.1
+		}
+	}
+	if n == nil {
+		return nil
+	}
+	n.Confidence = math.Round(n.Confidence*100) / 100
+	return n
+}
+
+func inferSeparatedName(a, b string) *InferredName {
+	switch {
+	case isGivenName(a) && isSurname(b):
+		return &InferredName{FirstName: capitalize(a), LastName: capitalize(b), Pattern: NamePatternFirstLast, Confidence: nameConfidence(0.9, a, b)}
+	case isSurname(a) && isGivenName(b):
+		return &InferredName{FirstName: capitalize(b), LastName: capitalize(a), Pattern: NamePatternLastFirst, Confidence: nameConfidence(0.75, b, a)}
+	case len(a) == 1 && isSurname(b):
+		return &InferredName{FirstInitial: strings.ToUpper(a), LastName: capitalize(b), Pattern: NamePatternInitialLast, Confidence: 0.8}
+	case isGivenName(a) && len(b) == 1:
+		return &InferredName{FirstName: capitalize(a), LastInitial: strings.ToUpper(b), Pattern: NamePatternFirstInitial, Confidence: 0.7}
+	case isGivenName(a) && len(b) >= minNameLen:
+		// most surnames are not in the list, an unknown second token is still likely one
+		return &InferredName{FirstName: capitalize(a), LastName: capitalize(b), Pattern: NamePatternFirstLast, Confidence: 0.6}
+	case len(a) == 1 && len(b) >= minNameLen:
+		return &InferredName{FirstInitial: strings.ToUpper(a), LastName: capitalize(b), Pattern: NamePatternInitialLast, Confidence: 0.5}
+	case len(a) >= minNameLen && isSurname(b):
+		return &InferredName{FirstName: capitalize(a), LastName: capitalize(b), Pattern: NamePatternFirstLast, Confidence: 0.5}
+	}
+	return nil
+}
+
+// inferJoinedName tries every split point of a local part without separators and keeps
+// the most likely reading.
+func inferJoinedName(s string) *InferredName {
+	var best *InferredName
+	consider := func(n *InferredName) {
+		if best == nil || n.Confidence > best.Confidence {
+			best = n
+		}
+	}
+
+	switch {
+	case isGivenName(s) && isSurname(s):
+		consider(&InferredName{FirstName: capitalize(s), Pattern: NamePatternFirst, Confidence: 0.4})
+	case isGivenName(s):
+		consider(&InferredName{FirstName: capitalize(s), Pattern: NamePatternFirst, Confidence: 0.6})
+	case isSurname(s):
+		consider(&InferredName{LastName: capitalize(s), Pattern: NamePatternLast, Confidence: 0.4})
+	}
+
+	for i := minNameLen; i+minNameLen <= len(s); i++ {
+		head, tail := s[:i], s[i:]
+		switch {
+		case isGivenName(head) && isSurname(tail):
+			consider(&InferredName{FirstName: capitalize(head), LastName: capitalize(tail), Pattern: NamePatternFirstLastJoined, Confidence: nameConfidence(0.75, head, tail)})
+		case isSurname(head) && isGivenName(tail):
+			consider(&InferredName{FirstName: capitalize(tail), LastName: capitalize(head), Pattern: NamePatternLastFirstJoined, Confidence: nameConfidence(0.55, tail, head)})
+		}
+	}
+
+	if len(s) > minNameLen+1 && isSurname(s[1:]) {
+		consider(&InferredName{FirstInitial: strings.ToUpper(s[:1]), LastName: capitalize(s[1:]), Pattern: NamePatternInitialLastJoined, Confidence: 0.65})
+	}
+	return best
+}
+
+// nameConfidence lowers base when the names could as well be read the other way round,
+// e.g. "thomas.james".
+func nameConfidence(base float64, first, last string) float64 {
+	if isSurname(first) && isGivenName(last) {
+		return base - 0.2
+	}
+	return base
+}
+
+func isGivenName(s string) bool {
+	return len(s) >= minNameLen && givenNames.contains(s)
+}
+
+func isSurname(s string) bool {
+	return len(s) >= minNameLen && surnames.contains(s)
+}
+
+// isLetters reports an ASCII-only token, the name lists do not hold anything else.
+func isLetters(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,118 @@
+package emailverifier
+
+import (
+	"errors"
+	"net/textproto"
+	"time"
+)
+
+const (
+	CacheGravatar = "gravatar"
+	CacheMTASTS   = "mta_sts"
+)
+
+// Observer receives events while a Verifier works. Observe is called synchronously
+// from the verifying goroutine, possibly from many goroutines at once, so it must be
+// fast and safe for concurrent use.
+type Observer interface {
+	Observe(event Event)
+}
+
+// Event is one of DNSLookupEvent, DialEvent, SMTPCommandEvent, APICallEvent,
+// CacheEvent or ResultEvent.
+type Event interface {
+	event()
+}
+
+type ObserverFunc func(event Event)
+
+func (f ObserverFunc) Observe(event Event) {
+	f(event)
+}
+
+type multiObserver []Observer
+
+func (m multiObserver) Observe(event Event) {
+	for _, o := range m {
+		o.Observe(event)
+	}
+}
+
+// MultiObserver forwards events to all observers in order.
+func MultiObserver(observers ...Observer) Observer {
+	return multiObserver(observers)
+}
+
+type DNSLookupEvent struct {
+	// Type is the record type: MX, IP or TLSA.
+	Type     string
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+type DialEvent struct {
+	MX       string
+	RemoteIP string
+	SourceIP string
+	Proxy    string
+	Duration time.Duration
+	Err      error
+}
+
+type SMTPCommandEvent struct {
+	MX string
+	// Command is the SMTP verb without arguments, so recipients are never reported.
+	Command  string
+	Code     int
+	Duration time.Duration
+	Err      error
+}
+
+type APICallEvent struct {
+	API      string
+	Duration time.Duration
+	Err      error
+}
+
+type CacheEvent struct {
+	Cache string
+	Hit   bool
+}
+
+type ResultEvent struct {
+	Email    string
+	Result   *Result
+	Duration time.Duration
+	Err      error
+}
+
+func (DNSLookupEvent) event()   {}
+func (DialEvent) event()        {}
+func (SMTPCommandEvent) event() {}
+func (APICallEvent) event()     {}
+func (CacheEvent) event()       {}
+func (ResultEvent) event()      {}
+
+func (v *Verifier) Observer(observer Observer) *Verifier {
+	v.observer = observer
+	return v
+}
+
+func (v *Verifier) observe(event Event) {
+	if v.observer != nil {
+		v.observer.Observe(event)
+	}
+}
+
+// smtpReplyCode extracts the reply code of a failed SMTP command, 250 when it succeeded.
+func smtpReplyCode(err error) int {
+	if err == nil {
+		return 250
+	}
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code
+	}
+	return 0
+}
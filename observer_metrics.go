@@ -0,0 +1,204 @@
+package emailverifier
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var metricsDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// MetricsCollector is an Observer aggregating events into counters and histograms. It
+// serves them in the Prometheus text exposition format, so it can be scraped without
+// pulling a Prometheus client into the package.
+type MetricsCollector struct {
+	mu         sync.Mutex
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+var metricsHelp = map[string]string{
+	"emailverifier_dns_lookups_total":             "DNS lookups by record type and outcome.",
+	"emailverifier_dns_lookup_duration_seconds":   "DNS lookup latency by record type.",
+	"emailverifier_dials_total":                   "Connection attempts to MX hosts by outcome.",
+	"emailverifier_dial_duration_seconds":         "Time to connect to an MX host.",
+	"emailverifier_smtp_commands_total":           "SMTP commands by verb and reply code.",
+	"emailverifier_smtp_command_duration_seconds": "SMTP command round trip by verb.",
+	"emailverifier_api_calls_total":               "API verifier calls by API and outcome.",
+	"emailverifier_api_call_duration_seconds":     "API verifier call latency by API.",
+	"emailverifier_cache_lookups_total":           "Cache lookups by cache and outcome.",
+	"emailverifier_verifications_total":           "Finished verifications by reachability.",
+	"emailverifier_verification_duration_seconds": "Time to verify an address.",
+	"emailverifier_stage_duration_seconds":        "Time spent per verification stage by recognized mail provider.",
+}
+
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{
+		counters:   map[string]map[string]float64{},
+		histograms: map[string]map[string]*histogram{},
+	}
+}
+
+func (m *MetricsCollector) Observe(event Event) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	switch e := event.(type) {
+	case DNSLookupEvent:
+		labels := labelString("type", e.Type)
+		m.inc("emailverifier_dns_lookups_total", labelString("type", e.Type, "result", outcome(e.Err)))
+		m.observeSeconds("emailverifier_dns_lookup_duration_seconds", labels, e.Duration.Seconds())
+	case DialEvent:
+		m.inc("emailverifier_dials_total", labelString("result", outcome(e.Err)))
+		m.observeSeconds("emailverifier_dial_duration_seconds", "", e.Duration.Seconds())
+	case SMTPCommandEvent:
+		m.inc("emailverifier_smtp_commands_total", labelString("command", e.Command, "code", strconv.Itoa(e.Code)))
+		m.observeSeconds("emailverifier_smtp_command_duration_seconds", labelString("command", e.Command), e.Duration.Seconds())
+	case APICallEvent:
+		m.inc("emailverifier_api_calls_total", labelString("api", e.API, "result", outcome(e.Err)))
+		m.observeSeconds("emailverifier_api_call_duration_seconds", labelString("api", e.API), e.Duration.Seconds())
+	case CacheEvent:
+		result := "miss"
+		if e.Hit {
+			result = "hit"
+		}
+		m.inc("emailverifier_cache_lookups_total", labelString("cache", e.Cache, "result", result))
+	case ResultEvent:
+		reachable := "error"
+		if e.Err == nil && e.Result != nil {
+			reachable = e.Result.Reachable
+		}
+		m.inc("emailverifier_verifications_total", labelString("reachable", reachable))
+		m.observeSeconds("emailverifier_verification_duration_seconds", "", e.Duration.Seconds())
+		if e.Result != nil {
+			m.observeStages(e.Result)
+		}
+	}
+}
+
+// observeStages records the stage timings of r. Only providers recognized from the
+// server banner get their own label, to keep the number of series bounded.
+func (m *MetricsCollector) observeStages(r *Result) {
+	provider := "other"
+	if r.SMTP != nil && r.SMTP.Server != nil && r.SMTP.Server.Provider != "" {
+		provider = r.SMTP.Server.Provider
+	}
+	for stage, ms := range r.Timings.stages() {
+		if ms > 0 {
+			m.observeSeconds("emailverifier_stage_duration_seconds", labelString("stage", stage, "provider", provider), float64(ms)/1000)
+		}
+	}
+}
+
+func (m *MetricsCollector) inc(name, labels string) {
+	series, ok := m.counters[name]
+	if !ok {
+		series = map[string]float64{}
+		m.counters[name] = series
+	}
+	series[labels]++
+}
+
+func (m *MetricsCollector) observeSeconds(name, labels string, value float64) {
+	series, ok := m.histograms[name]
+	if !ok {
+		series = map[string]*histogram{}
+		m.histograms[name] = series
+	}
+	h, ok := series[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(metricsDurationBuckets))}
+		series[labels] = h
+	}
+	for i, bound := range metricsDurationBuckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.sum += value
+	h.count++
+}
+
+// WritePrometheus writes all metrics in the Prometheus text exposition format.
+func (m *MetricsCollector) WritePrometheus(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	for _, name := range sortedKeys(m.counters) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, metricsHelp[name], name)
+		series := m.counters[name]
+		for _, labels := range sortedKeys(series) {
+			fmt.Fprintf(&b, "%s%s %g\n", name, wrapLabels(labels), series[labels])
+		}
+	}
+	for _, name := range sortedKeys(m.histograms) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s histogram\n", name, metricsHelp[name], name)
+		series := m.histograms[name]
+		for _, labels := range sortedKeys(series) {
+			h := series[labels]
+			for i, bound := range metricsDurationBuckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, wrapLabels(joinLabels(labels, labelString("le", strconv.FormatFloat(bound, 'g', -1, 64)))), h.counts[i])
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, wrapLabels(joinLabels(labels, `le="+Inf"`)), h.count)
+			fmt.Fprintf(&b, "%s_sum%s %g\n", name, wrapLabels(labels), h.sum)
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, wrapLabels(labels), h.count)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func (m *MetricsCollector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = m.WritePrometheus(w)
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+// labelString renders key/value pairs as `k1="v1",k2="v2"`.
+func labelString(kv ...string) string {
+	parts := make([]string, 0, len(kv)/2)
+	for i := 0; i+1 < len(kv); i += 2 {
+		parts = append(parts, kv[i]+"="+strconv.Quote(kv[i+1]))
+	}
+	return strings.Join(parts, ",")
+}
+
+func joinLabels(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+func wrapLabels(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package emailverifier
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+)
+
+type slogObserver struct {
+	logger *slog.Logger
+}
+
+// NewSlogObserver logs every event at debug level, and failed events and results at
+// warn and info level. Results name the domain only, the address is added when the
+// logger is enabled for debug level.
+func NewSlogObserver(logger *slog.Logger) Observer {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &slogObserver{logger: logger}
+}
+
+func (o *slogObserver) Observe(event Event) {
+	var msg string
+	var err error
+	var attrs []slog.Attr
+	level := slog.LevelDebug
+
+	switch e := event.(type) {
+	case DNSLookupEvent:
+		msg, err = "dns lookup", e.Err
+		attrs = []slog.Attr{slog.String("type", e.Type), slog.String("name", e.Name), slog.Duration("duration", e.Duration)}
+	case DialEvent:
+		msg, err = "dial", e.Err
+		attrs = []slog.Attr{slog.String("mx", e.MX), slog.String("remote_ip", e.RemoteIP), slog.String("source_ip", e.SourceIP),
+			slog.String("proxy", e.Proxy), slog.Duration("duration", e.Duration)}
+	case SMTPCommandEvent:
+		msg, err = "smtp command", e.Err
+		attrs = []slog.Attr{slog.String("mx", e.MX), slog.String("command", e.Command), slog.Int("code", e.Code),
+			slog.Duration("duration", e.Duration)}
+	case APICallEvent:
+		msg, err = "api call", e.Err
+		attrs = []slog.Attr{slog.String("api", e.API), slog.Duration("duration", e.Duration)}
+	case CacheEvent:
+		msg = "cache lookup"
+		attrs = []slog.Attr{slog.String("cache", e.Cache), slog.Bool("hit", e.Hit)}
+	case ResultEvent:
+		msg, err = "verification", e.Err
+		level = slog.LevelInfo
+		domain := ""
+		if i := strings.LastIndexByte(e.Email, '@'); i >= 0 {
+			domain = e.Email[i+1:]
+		}
+		attrs = []slog.Attr{slog.String("domain", domain), slog.Duration("duration", e.Duration)}
+		if o.logger.Enabled(context.Background(), slog.LevelDebug) {
+			attrs = append(attrs, slog.String("email", e.Email))
+		}
+		if e.Result != nil {
+			attrs = append(attrs, slog.String("reachable", e.Result.Reachable))
+		}
+	default:
+		return
+	}
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	o.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
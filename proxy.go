@@ -0,0 +1,430 @@
+package emailverifier
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+const (
+	ProxySelectRoundRobin = "round_robin"
+	ProxySelectLeastUsed  = "least_used"
+
+	defaultProxyMaxFailures = 3
+	defaultProxyEjectFor    = 5 * time.Minute
+
+	maxStickyDomains    = 10000
+	stickyDomainIdleFor = 30 * time.Minute
+)
+
+var ErrNoProxyAvailable = errors.New("no healthy proxy available")
+
+// SOCKS replies telling the proxy could not reach the destination, which is not the
+// proxy's fault.
+var socksDestinationReplies = []string{"network unreachable", "host unreachable", "connection refused", "TTL expired"}
+
+// proxyError marks failures of the proxy hop itself: reaching the proxy, the SOCKS
+// handshake or a refused CONNECT. Only these count against the proxy's health.
+type proxyError struct {
+	err error
+}
+
+func (e *proxyError) Error() string {
+	return e.err.Error()
+}
+
+func (e *proxyError) Unwrap() error {
+	return e.err
+}
+
+// proxyDialer dials through a single SOCKS5 or HTTP CONNECT proxy.
+type proxyDialer struct {
+	uri      string
+	redacted string
+	dialer   proxy.ContextDialer
+	socks    bool
+}
+
+func newProxyDialer(proxyURI string) (*proxyDialer, error) {
+	u, err := url.Parse(proxyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	var dialer proxy.ContextDialer
+	switch u.Scheme {
+	case "http":
+		dialer = &httpConnectDialer{proxyURL: u}
+	default:
+		d, err := proxy.FromURL(u, proxyHopDialer{})
+		if err != nil {
+			return nil, err
+		}
+		cd, ok := d.(proxy.ContextDialer)
+		if !ok {
+			cd = contextDialer{d}
+		}
+		dialer = cd
+	}
+	socks := strings.HasPrefix(u.Scheme, "socks")
+	return &proxyDialer{uri: proxyURI, redacted: u.Redacted(), dialer: dialer, socks: socks}, nil
+}
+
+func (p *proxyDialer) dial(ctx context.Context, addr string) (net.Conn, error) {
+	conn, err := p.dialer.DialContext(ctx, "tcp", addr)
+	if err != nil && p.socks && isSOCKSHandshakeFailure(err) {
+		return nil, &proxyError{err}
+	}
+	return conn, err
+}
+
+// isSOCKSHandshakeFailure reports whether err failed the SOCKS handshake rather than the
+// connection from the proxy to the destination. Timeouts are left out, the proxy may
+// still be waiting on the destination.
+func isSOCKSHandshakeFailure(err error) bool {
+	var opErr *net.OpError
+	var pxErr *proxyError
+	if errors.As(err, &pxErr) || !errors.As(err, &opErr) || !strings.HasPrefix(opErr.Op, "socks") {
+		return false
+	}
+	var netErr net.Error
+	if errors.As(opErr.Err, &netErr) && netErr.Timeout() || errors.Is(opErr.Err, context.Canceled) || errors.Is(opErr.Err, context.DeadlineExceeded) {
+		return false
+	}
+	for _, reply := range socksDestinationReplies {
+		if strings.HasSuffix(opErr.Err.Error(), reply) {
+			return false
+		}
+	}
+	return true
+}
+
+// proxyHopDialer connects to the proxy itself, marking its errors as proxy failures.
+type proxyHopDialer struct{}
+
+func (d proxyHopDialer) Dial(network, addr string) (net.Conn, error) {
+	return d.DialContext(context.Background(), network, addr)
+}
+
+func (proxyHopDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	var nd net.Dialer
+	conn, err := nd.DialContext(ctx, network, addr)
+	if err != nil {
+		return nil, &proxyError{err}
+	}
+	return conn, nil
+}
+
+// contextDialer adapts dialers registered with proxy.RegisterDialerType that do not
+// implement proxy.ContextDialer.
+type contextDialer struct {
+	proxy.Dialer
+}
+
+func (d contextDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		conn, err := d.Dial(network, addr)
+		ch <- result{conn, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.conn, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-ch; r.conn != nil {
+				r.conn.Close()
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+type httpConnectDialer struct {
+	proxyURL *url.URL
+}
+
+func (d *httpConnectDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := proxyHopDialer{}.DialContext(ctx, network, d.proxyURL.Host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if u := d.proxyURL.User; u != nil {
+		password, _ := u.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(u.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+	if err = req.Write(conn); err != nil {
+		conn.Close()
+		return nil, &proxyError{err}
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		conn.Close()
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			// the proxy may still be waiting on the destination
+			return nil, err
+		}
+		return nil, &proxyError{err}
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		conn.Close()
+		err = fmt.Errorf("proxy CONNECT %s: %s", addr, resp.Status)
+		if resp.StatusCode == http.StatusBadGateway || resp.StatusCode == http.StatusGatewayTimeout {
+			// the proxy could not reach the destination
+			return nil, err
+		}
+		return nil, &proxyError{err}
+	}
+	_ = conn.SetDeadline(time.Time{})
+	return &bufferedConn{Conn: conn, r: br}, nil
+}
+
+// bufferedConn keeps bytes the server sent right after the CONNECT response.
+type bufferedConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.r.Read(b)
+}
+
+type ProxyStats struct {
+	Proxy        string    `json:"proxy"`
+	Uses         uint64    `json:"uses"`
+	InUse        int       `json:"in_use"`
+	Failures     int       `json:"failures"`
+	EjectedUntil time.Time `json:"ejected_until,omitempty"`
+}
+
+type pooledProxy struct {
+	*proxyDialer
+	uses         uint64
+	inUse        int
+	failures     int
+	ejectedUntil time.Time
+}
+
+// ProxyPool hands out proxies to verifications and temporarily ejects proxies that
+// keep failing. A pool is safe for concurrent use and meant to be shared by verifiers.
+type ProxyPool struct {
+	mu          sync.Mutex
+	proxies     []*pooledProxy
+	strategy    string
+	next        int
+	maxFailures int
+	ejectFor    time.Duration
+	sticky      bool
+	domains     map[string]stickyProxy
+}
+
+type stickyProxy struct {
+	proxy    *pooledProxy
+	lastUsed time.Time
+}
+
+func NewProxyPool(proxyURIs []string) (*ProxyPool, error) {
+	if len(proxyURIs) == 0 {
+		return nil, errors.New("proxy pool needs at least one proxy")
+	}
+	p := &ProxyPool{
+		strategy:    ProxySelectRoundRobin,
+		maxFailures: defaultProxyMaxFailures,
+		ejectFor:    defaultProxyEjectFor,
+		domains:     map[string]stickyProxy{},
+	}
+	for _, uri := range proxyURIs {
+		d, err := newProxyDialer(uri)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", uri, err)
+		}
+		p.proxies = append(p.proxies, &pooledProxy{proxyDialer: d})
+	}
+	return p, nil
+}
+
+func (p *ProxyPool) Strategy(strategy string) *ProxyPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.strategy = strategy
+	return p
+}
+
+// EjectAfter ejects a proxy for ejectFor once it failed maxFailures times in a row.
+func (p *ProxyPool) EjectAfter(maxFailures int, ejectFor time.Duration) *ProxyPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.maxFailures = maxFailures
+	p.ejectFor = ejectFor
+	return p
+}
+
+// StickyDomains keeps using the same proxy for a domain as long as it is healthy.
+func (p *ProxyPool) StickyDomains(sticky bool) *ProxyPool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sticky = sticky
+	return p
+}
+
+func (p *ProxyPool) Stats() []ProxyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]ProxyStats, 0, len(p.proxies))
+	for _, px := range p.proxies {
+		stats = append(stats, ProxyStats{
+			Proxy:        px.redacted,
+			Uses:         px.uses,
+			InUse:        px.inUse,
+			Failures:     px.failures,
+			EjectedUntil: px.ejectedUntil,
+		})
+	}
+	return stats
+}
+
+func (p *ProxyPool) acquire(domain string) (*pooledProxy, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	if p.sticky {
+		if s, ok := p.domains[domain]; ok && s.proxy.healthy(now) {
+			p.domains[domain] = stickyProxy{s.proxy, now}
+			s.proxy.acquired()
+			return s.proxy, nil
+		}
+	}
+
+	var selected *pooledProxy
+	switch p.strategy {
+	case ProxySelectLeastUsed:
+		for _, px := range p.proxies {
+			if !px.healthy(now) {
+				continue
+			}
+			if selected == nil || px.inUse < selected.inUse ||
+				(px.inUse == selected.inUse && px.uses < selected.uses) {
+				selected = px
+			}
+		}
+	default:
+		for i := 0; i < len(p.proxies); i++ {
+			px := p.proxies[(p.next+i)%len(p.proxies)]
+			if px.healthy(now) {
+				selected = px
+				p.next = (p.next + i + 1) % len(p.proxies)
+				break
+			}
+		}
+	}
+	if selected == nil {
+		return nil, ErrNoProxyAvailable
+	}
+	if p.sticky {
+		if len(p.domains) >= maxStickyDomains {
+			p.pruneStickyDomains(now)
+		}
+		p.domains[domain] = stickyProxy{selected, now}
+	}
+	selected.acquired()
+	return selected, nil
+}
+
+// pruneStickyDomains forgets domains not verified for a while, and all of them if that
+// is not enough.
+func (p *ProxyPool) pruneStickyDomains(now time.Time) {
+	for domain, s := range p.domains {
+		if now.Sub(s.lastUsed) > stickyDomainIdleFor {
+			delete(p.domains, domain)
+		}
+	}
+	if len(p.domains) >= maxStickyDomains {
+		clear(p.domains)
+	}
+}
+
+func (p *ProxyPool) release(px *pooledProxy, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	px.inUse--
+	if !failed {
+		px.failures = 0
+		return
+	}
+	px.failures++
+	if p.maxFailures > 0 && px.failures >= p.maxFailures {
+		px.ejectedUntil = time.Now().Add(p.ejectFor)
+		px.failures = 0
+	}
+}
+
+func (px *pooledProxy) healthy(now time.Time) bool {
+	return now.After(px.ejectedUntil)
+}
+
+func (px *pooledProxy) acquired() {
+	px.uses++
+	px.inUse++
+}
+
+func (v *Verifier) ProxyPool(pool *ProxyPool) *Verifier {
+	v.proxyPool = pool
+	return v
+}
+
+// acquireProxy returns the proxy dialer for a verification of domain, nil when no proxy
+// is configured, and a release func to report the result of dialing the MX through it.
+func (v *Verifier) acquireProxy(domain string) (*proxyDialer, func(error), error) {
+	if v.proxyPool != nil {
+		px, err := v.proxyPool.acquire(domain)
+		if err != nil {
+			return nil, nil, err
+		}
+		return px.proxyDialer, func(err error) { v.proxyPool.release(px, isProxyFailure(err)) }, nil
+	}
+	if v.proxyURI != "" {
+		d, err := newProxyDialer(v.proxyURI)
+		if err != nil {
+			return nil, nil, err
+		}
+		return d, func(error) {}, nil
+	}
+	return nil, func(error) {}, nil
+}
+
+// isProxyFailure reports whether err from newSMTPClient comes from the proxy hop. MX
+// lookups, unreachable MX hosts and their replies say nothing about the proxy.
+func isProxyFailure(err error) bool {
+	var pxErr *proxyError
+	return errors.As(err, &pxErr)
+}
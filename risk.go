@@ -0,0 +1,299 @@
+package emailverifier
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+const (
+	RiskTrapLocalPart   = "trap_local_part"
+	RiskTrapDomain      = "trap_domain"
+	RiskKeyboardPattern = "keyboard_pattern"
+	RiskRepeated        = "repeated_characters"
+	RiskSequence        = "sequence"
+	RiskHighEntropy     = "high_entropy"
+	RiskFewVowels       = "few_vowels"
+	RiskConsonantRun    = "consonant_run"
+	RiskDigitMix        = "digit_mix"
+	RiskSuspiciousTLD   = "suspicious_tld"
+	RiskNumericDomain   = "numeric_domain"
+
+	// shorter runs turn up in names, e.g. "reza" or "hertz"
+	minKeyboardPatternLen = 5
+	// German names reach 7 consonants in a row, e.g. "kirchschlager"
+	minConsonantRun = 8
+)
+
+// Risk explains the heuristic risk assessment of an address. Score goes from 0 to 1.
+type Risk struct {
+	Score   float64  `json:"score"`
+	Entropy float64  `json:"entropy"`
+	Reasons []string `json:"reasons"`
+}
+
+var (
+	riskWeights = map[string]float64{
+		RiskTrapLocalPart:   0.8,
+		RiskTrapDomain:      0.8,
+		RiskKeyboardPattern: 0.6,
+		RiskRepeated:        0.5,
+		RiskSequence:        0.2,
+		RiskHighEntropy:     0.4,
+		RiskFewVowels:       0.4,
+		RiskConsonantRun:    0.4,
+		RiskDigitMix:        0.4,
+		RiskSuspiciousTLD:   0.2,
+		RiskNumericDomain:   0.2,
+	}
+	gibberishReasons = []string{RiskKeyboardPattern, RiskRepeated, RiskHighEntropy, RiskFewVowels, RiskConsonantRun, RiskDigitMix}
+	spamTrapReasons  = []string{RiskTrapLocalPart, RiskTrapDomain}
+
+	// QWERTY rows are matched both ways, the QWERTZ and AZERTY ones forwards only
+	keyboardRows      = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm"}
+	localKeyboardRows = []string{"qwertzuiop", "yxcvbnm", "azertyuiop", "qsdfghjklm", "wxcvbn"}
+
+	trapLocalParts = map[string]bool{
+		"abuse": true, "postmaster": true, "hostmaster": true, "spam": true, "spamtrap": true,
+		"trap": true, "honeypot": true, "blackhole": true, "devnull": true, "nospam": true,
+		"antispam": true, "junk": true, "fbl": true, "spamreport": true, "bounce": true,
+	}
+	trapKeywords = []string{"spamtrap", "honeypot", "blackhole", "spam-trap", "spam_trap"}
+
+	suspiciousTLDs = map[string]bool{
+		"tk": true, "ml": true, "ga": true, "cf": true, "gq": true, "xyz": true, "top": true,
+		"click": true, "buzz": true, "icu": true, "loan": true, "work": true, "rest": true,
+		"monster": true, "cyou": true, "sbs": true, "cfd": true,
+	}
+)
+
+func (r *Risk) has(reasons ...string) bool {
+	for _, reason := range reasons {
+		for _, got := range r.Reasons {
+			if got == reason {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Gibberish reports a local part that looks like keyboard mash rather than a name or word.
+func (r *Risk) Gibberish() bool {
+	return r.has(gibberishReasons...)
+}
+
+// PossibleSpamTrap reports an address matching patterns commonly used for spam traps.
+func (r *Risk) PossibleSpamTrap() bool {
+	return r.has(spamTrapReasons...)
+}
+
+// AssessRisk scores username@domain on local part entropy, keyboard patterns, repeated
+// characters, known trap patterns and domain markers. It does no network lookups.
+func (v *Verifier) AssessRisk(username, domain string) *Risk {
+	r := &Risk{Reasons: []string{}}
+	local := strings.ToLower(username)
+	if i := strings.IndexByte(local, '+'); i > 0 {
+		local = local[:i]
+	}
+	compact := stripRoleSeparators(local)
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	if trapLocalParts[compact] || trapLocalParts[strings.TrimRight(compact, "0123456789")] || containsAny(compact, trapKeywords) {
+		r.add(RiskTrapLocalPart)
+	}
+	if containsAny(domain, trapKeywords) {
+		r.add(RiskTrapDomain)
+	}
+
+	letters := strings.Map(func(c rune) rune {
+		if c >= 'a' && c <= 'z' {
+			return c
+		}
+		return -1
+	}, compact)
+
+	if hasKeyboardPattern(letters) {
+		r.add(RiskKeyboardPattern)
+	}
+	if hasRepetition(compact) {
+		r.add(RiskRepeated)
+	}
+	if hasSequence(compact) {
+		r.add(RiskSequence)
+	}
+
+	r.Entropy = math.Round(shannonEntropy(compact)*100) / 100
+	vowels := strings.Count(letters, "a") + strings.Count(letters, "e") + strings.Count(letters, "i") +
+		strings.Count(letters, "o") + strings.Count(letters, "u") + strings.Count(letters, "y")
+	vowelRatio := 1.0
+	if len(letters) > 0 {
+		vowelRatio = float64(vowels) / float64(len(letters))
+	}
+	switch {
+	case (len(letters) >= 5 && vowels == 0) || (len(letters) >= 8 && vowelRatio < 0.1):
+		r.add(RiskFewVowels)
+	case len(compact) >= 12 && r.Entropy >= 3.5 && vowelRatio < 0.3:
+		r.add(RiskHighEntropy)
+	}
+	// counted per token, initials next to a name like "jm.schmidt" are no run
+	for _, token := range strings.FieldsFunc(local, isRoleSeparator) {
+		if longestConsonantRun(token) >= minConsonantRun {
+			r.add(RiskConsonantRun)
+			break
+		}
+	}
+	if letterDigitSwitches(compact) >= 5 {
+		r.add(RiskDigitMix)
+	}
+
+	sld, tld := splitDomain(domain)
+	if suspiciousTLDs[tld] {
+		r.add(RiskSuspiciousTLD)
+	}
+	if digits := countDigits(sld); len(sld) > 0 && float64(digits)/float64(len(sld)) >= 0.4 {
+		r.add(RiskNumericDomain)
+	}
+
+	for _, reason := range r.Reasons {
+		r.Score += riskWeights[reason]
+	}
+	r.Score = math.Round(math.Min(r.Score, 1)*100) / 100
+	return r
+}
+
+func (r *Risk) add(reason string) {
+	r.Reasons = append(r.Reasons, reason)
+}
+
+func hasKeyboardPattern(letters string) bool {
+	rows := slices.Concat(keyboardRows, localKeyboardRows)
+	for _, row := range keyboardRows {
+		rows = append(rows, reverseString(row))
+	}
+	for _, row := range rows {
+		for i := 0; i+minKeyboardPatternLen <= len(row); i++ {
+			if strings.Contains(letters, row[i:i+minKeyboardPatternLen]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasRepetition finds a character repeated 4 times or a 2-3 character unit repeated 3
+// times in a row, e.g. "aaaa" or "abcabcabc".
+func hasRepetition(s string) bool {
+	for unit := 1; unit <= 3; unit++ {
+		need := 3
+		if unit == 1 {
+			need = 4
+		}
+		for i := 0; i+unit*need <= len(s); i++ {
+			count := 1
+			for j := i + unit; j+unit <= len(s) && s[j:j+unit] == s[i:i+unit]; j += unit {
+				count++
+			}
+			if count >= need {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasSequence finds 4 consecutive ascending or descending letters or digits, e.g. "abcd"
+// or "4321".
+func hasSequence(s string) bool {
+	up, down := 1, 1
+	for i := 1; i < len(s); i++ {
+		sameClass := isDigit(s[i]) == isDigit(s[i-1]) && isAlnum(s[i]) && isAlnum(s[i-1])
+		switch {
+		case sameClass && s[i] == s[i-1]+1:
+			up, down = up+1, 1
+		case sameClass && s[i] == s[i-1]-1:
+			up, down = 1, down+1
+		default:
+			up, down = 1, 1
+		}
+		if up >= 4 || down >= 4 {
+			return true
+		}
+	}
+	return false
+}
+
+func shannonEntropy(s string) float64 {
+	if s == "" {
+		return 0
+	}
+	counts := map[rune]int{}
+	for _, c := range s {
+		counts[c]++
+	}
+	var h float64
+	n := float64(len([]rune(s)))
+	for _, c := range counts {
+		p := float64(c) / n
+		h -= p * math.Log2(p)
+	}
+	return h
+}
+
+func longestConsonantRun(s string) int {
+	longest, run := 0, 0
+	for _, c := range s {
+		if c < 'a' || c > 'z' || strings.ContainsRune("aeiouy", c) {
+			run = 0
+			continue
+		}
+		run++
+		longest = max(longest, run)
+	}
+	return longest
+}
+
+func letterDigitSwitches(s string) int {
+	switches := 0
+	for i := 1; i < len(s); i++ {
+		if isAlnum(s[i]) && isAlnum(s[i-1]) && isDigit(s[i]) != isDigit(s[i-1]) {
+			switches++
+		}
+	}
+	return switches
+}
+
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if isDigit(s[i]) {
+			n++
+		}
+	}
+	return n
+}
+
+func containsAny(s string, substrs []string) bool {
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverseString(s string) string {
+	b := []byte(s)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return string(b)
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isAlnum(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'z')
+}
@@ -0,0 +1,49 @@
+package emailverifier
+
+import "testing"
+
+func TestAssessRisk(t *testing.T) {
+	v := NewVerifier()
+	tests := []struct {
+		username  string
+		gibberish bool
+		spamTrap  bool
+	}{
+		{"asdfgh123", true, false},
+		{"qzxtrwpln", true, false},
+		{"bcdfghjklmnp.smith", true, false},
+		{"abuse", false, true},
+		{"postmaster", false, true},
+		{"spamtrap42", false, true},
+
+		// real names with long consonant clusters
+		{"hp.schneider", false, false},
+		{"jm.schmidt", false, false},
+		{"kirchschlager", false, false},
+		{"john.smith", false, false},
+		{"maria.rosales", false, false},
+	}
+	for _, tt := range tests {
+		r := v.AssessRisk(tt.username, "example.com")
+		if r.Gibberish() != tt.gibberish || r.PossibleSpamTrap() != tt.spamTrap {
+			t.Errorf("AssessRisk(%q) = %v, want gibberish %v, spam trap %v", tt.username, r.Reasons, tt.gibberish, tt.spamTrap)
+		}
+	}
+}
+
+func TestLongestConsonantRun(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"kirchschlager", 7},
+		{"schmidt", 4},
+		{"bcd12fgh", 3},
+		{"aeiou", 0},
+	}
+	for _, tt := range tests {
+		if got := longestConsonantRun(tt.s); got != tt.want {
+			t.Errorf("longestConsonantRun(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package emailverifier
+
+import (
+	"strings"
+	"sync"
+)
+
+const (
+	RoleCategoryAdmin     = "admin"
+	RoleCategoryBilling   = "billing"
+	RoleCategoryGeneral   = "general"
+	RoleCategoryHR        = "hr"
+	RoleCategoryMarketing = "marketing"
+	RoleCategoryNoReply   = "no-reply"
+	RoleCategorySales     = "sales"
+	RoleCategorySupport   = "support"
+
+	// keywords shorter than this are only matched as whole tokens
+	minRoleAffixLength = 5
+)
+
+var (
+	// words completing a role keyword glued to them, e.g. "salesteam"
+	roleQualifiers = map[string]bool{
+		"team": true, "dept": true, "desk": true, "group": true, "center": true, "centre": true,
+		"mail": true, "box": true, "line": true, "queue": true,
+	}
+	roleKeywords           = map[string]string{}
+	customRoleSyncAccounts sync.Map
+)
+
+func init() {
+	for _, keywords := range localizedRoleAccounts {
+		for k, category := range keywords {
+			roleKeywords[k] = category
+		}
+	}
+}
+
+// RoleCategory returns the role category of username, or "" when it is not a role account.
+func (v *Verifier) RoleCategory(username string) string {
+	name := normalizeRoleUsername(username)
+	if name == "" {
+		return ""
+	}
+	compact := stripRoleSeparators(name)
+
+	for _, candidate := range []string{name, compact, strings.TrimRight(compact, "0123456789")} {
+		if category := roleKeywordCategory(candidate); category != "" {
+			return category
+		}
+	}
+
+	tokens := strings.FieldsFunc(name, isRoleSeparator)
+	if len(tokens) > 1 {
+		for _, i := range []int{0, len(tokens) - 1} {
+			token := strings.TrimRight(tokens[i], "0123456789")
+			category := roleKeywordCategory(token)
+			// short keywords such as "it" or "pr" are also initials and name parts
+			if category != "" && (len(token) >= minRoleAffixLength || onlyRoleTokens(tokens, i)) {
+				return category
+			}
+		}
+	}
+
+	for _, token := range tokens {
+		if category := roleAffixCategory(strings.TrimRight(token, "0123456789")); category != "" {
+			return category
+		}
+	}
+
+	if roleAccounts.contains(name) || roleAccounts.contains(compact) {
+		return RoleCategoryGeneral
+	}
+	return ""
+}
+
+func (v *Verifier) AddRoleAccounts(category string, usernames []string) *Verifier {
+	if category == "" {
+		category = RoleCategoryGeneral
+	}
+	for _, u := range usernames {
+		if name := stripRoleSeparators(normalizeRoleUsername(u)); name != "" {
+			customRoleSyncAccounts.Store(name, category)
+		}
+	}
+	return v
+}
+
+func (v *Verifier) RemoveRoleAccounts(usernames []string) *Verifier {
+	for _, u := range usernames {
+		customRoleSyncAccounts.Delete(stripRoleSeparators(normalizeRoleUsername(u)))
+	}
+	return v
+}
+
+func roleKeywordCategory(word string) string {
+	if word == "" {
+		return ""
+	}
+	if category, ok := customRoleSyncAccounts.Load(word); ok {
+		return category.(string)
+	}
+	return roleKeywords[word]
+}
+
+// onlyRoleTokens reports whether the tokens other than tokens[skip] are role keywords or
+// locales, e.g. "hr" and "de" in "hr.de".
+func onlyRoleTokens(tokens []string, skip int) bool {
+	for i, token := range tokens {
+		if i == skip {
+			continue
+		}
+		token = strings.TrimRight(token, "0123456789")
+		if _, locale := localizedRoleAccounts[token]; !locale && roleKeywordCategory(token) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// roleAffixCategory matches a token made of a role keyword glued to another role word,
+// e.g. "salesteam" or "nlsupport". Anything else, as in "rosales", is likely a name.
+func roleAffixCategory(token string) string {
+	var best, category string
+	match := func(k, c string) {
+		if c == RoleCategoryGeneral || len(k) < minRoleAffixLength || len(k) <= len(best) || len(k) >= len(token) {
+			return
+		}
+		if rest, ok := strings.CutPrefix(token, k); ok && isRoleWord(rest) {
+			best, category = k, c
+		} else if rest, ok := strings.CutSuffix(token, k); ok && isRoleWord(rest) {
+			best, category = k, c
+		}
+	}
+	for k, c := range roleKeywords {
+		match(k, c)
+	}
+	customRoleSyncAccounts.Range(func(key, value interface{}) bool {
+		match(key.(string), value.(string))
+		return true
+	})
+	return category
+}
+
+func isRoleWord(s string) bool {
+	_, locale := localizedRoleAccounts[s]
+	return locale || roleQualifiers[s] || roleKeywordCategory(s) != ""
+}
+
+func normalizeRoleUsername(username string) string {
+	name := strings.ToLower(strings.TrimSpace(username))
+	if i := strings.Index(name, "+"); i > 0 {
+		name = name[:i]
+	}
+	return strings.Trim(name, "._-")
+}
+
+func stripRoleSeparators(name string) string {
+	return strings.Map(func(r rune) rune {
+		if isRoleSeparator(r) {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+func isRoleSeparator(r rune) bool {
+	return r == '.' || r == '-' || r == '_'
+}
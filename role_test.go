@@ -0,0 +1,37 @@
+package emailverifier
+
+import "testing"
+
+func TestRoleCategory(t *testing.T) {
+	v := NewVerifier()
+	tests := []struct {
+		username string
+		want     string
+	}{
+		{"sales-team", RoleCategorySales},
+		{"salesteam", RoleCategorySales},
+		{"noreply+abc", RoleCategoryNoReply},
+		{"info.de", RoleCategoryGeneral},
+		{"kontakt", RoleCategoryGeneral},
+		{"ventas", RoleCategorySales},
+		{"billingdept", RoleCategoryBilling},
+		{"hr.de", RoleCategoryHR},
+		{"it", RoleCategoryAdmin},
+
+		// real people whose names contain or sit next to role keywords
+		{"jrosales", ""},
+		{"m.rosales", ""},
+		{"maria.rosales", ""},
+		{"jborders", ""},
+		{"john.it", ""},
+		{"it.smith", ""},
+		{"mary.pr", ""},
+		{"pr.john", ""},
+		{"john.smith", ""},
+	}
+	for _, tt := range tests {
+		if got := v.RoleCategory(tt.username); got != tt.want {
+			t.Errorf("RoleCategory(%q) = %q, want %q", tt.username, got, tt.want)
+		}
+	}
+}
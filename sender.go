@@ -0,0 +1,104 @@
+package emailverifier
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NullSender makes MAIL FROM use the null reverse-path, MAIL FROM:<>, as bounces do.
+// Some receivers only answer RCPT honestly for it, others refuse it.
+const NullSender = "<>"
+
+const helloNameLookupTimeout = 5 * time.Second
+
+// helloNameSyncCache maps source IPs to their forward-confirmed reverse DNS name, or ""
+// when they have none.
+var helloNameSyncCache sync.Map
+
+// FromEmailFor overrides the MAIL FROM address for recipients at domain. email may be
+// NullSender.
+func (v *Verifier) FromEmailFor(domain, email string) *Verifier {
+	v.senderOverrides[strings.ToLower(strings.TrimSuffix(domain, "."))] = email
+	return v
+}
+
+// EnableAutoHelloName sends the reverse DNS name of the source IP in EHLO when it
+// resolves back to the IP. The name set with HelloName is used otherwise, and behind
+// proxies, whose outgoing IP is unknown.
+func (v *Verifier) EnableAutoHelloName() *Verifier {
+	v.autoHelloName = true
+	return v
+}
+
+func (v *Verifier) DisableAutoHelloName() *Verifier {
+	v.autoHelloName = false
+	return v
+}
+
+// SMTPPort sets the port of MX hosts, 25 by default. Other ports are meant for local
+// stand-in servers and private relays.
+func (v *Verifier) SMTPPort(port int) *Verifier {
+	v.smtpPort = port
+	return v
+}
+
+// sender returns the MAIL FROM address for recipients at domain, "" for the null
+// reverse-path.
+func (v *Verifier) sender(domain string) string {
+	from, ok := v.senderOverrides[strings.ToLower(domain)]
+	if !ok {
+		from = v.fromEmail
+	}
+	if from == NullSender {
+		return ""
+	}
+	return from
+}
+
+// helloNameFor returns the name to greet with when connecting from ip.
+func (v *Verifier) helloNameFor(ip net.IP) string {
+	if !v.autoHelloName || ip == nil || ip.IsLoopback() {
+		return v.helloName
+	}
+	name, ok := helloNameSyncCache.Load(ip.String())
+	if !ok {
+		found, err := lookupHelloName(ip)
+		if err != nil {
+			return v.helloName
+		}
+		name, _ = helloNameSyncCache.LoadOrStore(ip.String(), found)
+	}
+	if name == "" {
+		return v.helloName
+	}
+	return name.(string)
+}
+
+// lookupHelloName returns the first PTR name of ip resolving back to it.
+func lookupHelloName(ip net.IP) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), helloNameLookupTimeout)
+	defer cancel()
+
+	names, err := net.DefaultResolver.LookupAddr(ctx, ip.String())
+	if err != nil {
+		if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	for _, name := range names {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, name)
+		if err != nil {
+			continue
+		}
+		for _, a := range addrs {
+			if a.IP.Equal(ip) {
+				return strings.TrimSuffix(name, "."), nil
+			}
+		}
+	}
+	return "", nil
+}
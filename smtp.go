@@ -7,116 +7,405 @@ import (
 	"math/rand"
 	"net"
 	"net/smtp"
-	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
-
-	"golang.org/x/net/proxy"
 )
 
 type SMTP struct {
-	HostExists  bool `json:"host_exists"`
-	FullInbox   bool `json:"full_inbox"`
-	CatchAll    bool `json:"catch_all"`
-	Deliverable bool `json:"deliverable"`
-	Disabled    bool `json:"disabled"`
+	HostExists bool `json:"host_exists"`
+	FullInbox  bool `json:"full_inbox"`
+	CatchAll   bool `json:"catch_all"`
+	// CatchAllConfidence is the estimated likelihood, from 0 to 1, that the domain
+	// accepts any recipient. CatchAll is set above 0.5, exactly 0.5 when no probe got a
+	// definite answer.
+	CatchAllConfidence float64 `json:"catch_all_confidence"`
+	Deliverable        bool    `json:"deliverable"`
+	Disabled           bool    `json:"disabled"`
+	// TemporaryFailure is set when the server deferred its answer for the address.
+	TemporaryFailure *TemporaryFailure `json:"temporary_failure,omitempty"`
+
+	Proxy    string `json:"proxy,omitempty"`
+	SourceIP string `json:"source_ip,omitempty"`
+	RemoteIP string `json:"remote_ip,omitempty"`
+	// Provider is the registered domain of the MX host, or the hosted mail service
+	// recognized from its banner.
+	Provider string `json:"provider,omitempty"`
+
+	TLS    *SMTPTLS    `json:"tls,omitempty"`
+	Server *SMTPServer `json:"server,omitempty"`
+	VRFY   *VRFYResult `json:"vrfy,omitempty"`
+	EXPN   *VRFYResult `json:"expn,omitempty"`
+
+	timings Timings
+}
+
+// smtpConn is an SMTP client together with where it is connected to.
+type smtpConn struct {
+	*smtp.Client
+	mx       *net.MX
+	remoteIP net.IP
+	observe  func(Event)
+	rec      *recordingConn
+	server   *SMTPServer
+	timings  Timings
+	// stop keeps the connection from being closed when the dial context is canceled
+	stop func() bool
+}
+
+func (c *smtpConn) Close() error {
+	c.stop()
+	return c.Client.Close()
+}
+
+// cmd runs an SMTP command and reports it to the observer.
+func (c *smtpConn) cmd(command string, f func() error) error {
+	start := time.Now()
+	err := f()
+	c.observe(SMTPCommandEvent{
+		MX:       c.mx.Host,
+		Command:  command,
+		Code:     smtpReplyCode(err),
+		Duration: time.Since(start),
+		Err:      err,
+	})
+	return err
+}
+
+// localIP returns the address the connection leaves from, or the proxy side of it.
+func (c *smtpConn) localIP() net.IP {
+	if addr, ok := c.rec.LocalAddr().(*net.TCPAddr); ok {
+		return addr.IP
+	}
+	return nil
+}
+
+func (c *smtpConn) hello(name string) error {
+	c.rec.record()
+	start := time.Now()
+	err := c.cmd("EHLO", func() error { return c.Hello(name) })
+	if lines := lastReplyLines(c.rec.stop()); err == nil {
+		c.server.addEHLO(lines, time.Since(start))
+	}
+	if c.server.Provider != "" {
+		storeProvider(c.mx.Host, c.server.Provider)
+	}
+	return err
+}
+
+func (c *smtpConn) mail(from string) error {
+	return c.cmd("MAIL", func() error { return c.Mail(from) })
 }
 
+func (c *smtpConn) rcpt(to string) error {
+	return c.cmd("RCPT", func() error { return c.Rcpt(to) })
+}
+
+var errNoMXRecords = errors.New("No MX records found")
+
 func (v *Verifier) CheckSMTP(domain, username string) (*SMTP, error) {
+	return v.checkSMTP(context.Background(), domain, username, nil)
+}
+
+// checkSMTP is CheckSMTP reusing the catch-all probes d has for the domain. Known
+// catch-all domains are not connected to again.
+func (v *Verifier) checkSMTP(ctx context.Context, domain, username string, d *domainState) (*SMTP, error) {
 	if !v.smtpCheckEnabled {
 		return nil, nil
 	}
+	if probes, ok := d.catchAllProbes(); ok && v.catchAllCheckEnabled {
+		if confidence := catchAllConfidence(probes, nil); isCatchAll(confidence) {
+			return &SMTP{HostExists: true, CatchAll: true, CatchAllConfidence: confidence}, nil
+		}
+	}
 
 	var ret SMTP
-	var err error
 	email := fmt.Sprintf("%s@%s", username, domain)
 
-	client, mx, err := newSMTPClient(domain, v.proxyURI, v.localAddr, v.connectTimeout, v.operationTimeout)
+	s, err := v.startSMTP(ctx, domain, &ret, d)
 	if err != nil {
-		return &ret, ParseSMTPError(err)
+		return &ret, err
 	}
-
-	defer client.Close()
-
-	for _, apiVerifier := range v.apiVerifiers {
-		if apiVerifier.isSupported(strings.ToLower(mx.Host)) {
-			return apiVerifier.check(domain, username)
+	defer s.close()
+	client := s.client
+
+	if s.api != nil {
+		start := time.Now()
+		res, err := s.api.check(domain, username)
+		if res != nil {
+			res.timings = ret.timings
+			res.timings.APIMs = since(start)
 		}
-	}
-
-	if err = client.Hello(v.helloName); err != nil {
-		return &ret, ParseSMTPError(err)
-	}
-
-	if err = client.Mail(v.fromEmail); err != nil {
-		return &ret, ParseSMTPError(err)
+		return res, err
 	}
 
 	ret.HostExists = true
 	ret.CatchAll = true
 
 	if v.catchAllCheckEnabled {
-		isCatchAll := true
-		for _, randomEmail := range GenerateSmartRandomEmails(domain, 2) {
-			if err = client.Rcpt(randomEmail); err != nil {
-				if e := ParseSMTPError(err); e != nil {
-					switch e.Message {
-					case ErrFullInbox:
-						ret.FullInbox = true
-					case ErrNotAllowed:
-						ret.Disabled = true
-					case ErrServerUnavailable:
-						isCatchAll = false
-					}
-				}
-				if !isCatchAll {
+		probes, known := d.catchAllProbes()
+		if !known {
+			start := time.Now()
+			probes = probeCatchAll(client, domain, v.catchAllProbes)
+			ret.timings.CatchAllMs = since(start)
+			for _, p := range probes {
+				s.flagBlocked(p.err, false)
+			}
+			d.setCatchAllProbes(probes)
+		}
+		for _, p := range probes {
+			if p.err == nil {
+				continue
+			}
+			switch p.err.Message {
+			case ErrFullInbox:
+				ret.FullInbox = true
+			case ErrNotAllowed:
+				ret.Disabled = true
+			}
+		}
+
+		var real *rcptProbe
+		if username != "" {
+			p := client.probeRcpt(email)
+			s.flagBlocked(p.err, false)
+			ret.timings.RCPTMs = p.duration.Milliseconds()
+			ret.Deliverable = p.accepted
+			ret.TemporaryFailure = newTemporaryFailure(p.err)
+			real = &p
+		}
+		if accepted, rejected := countProbes(probes); accepted+rejected == 0 && ret.TemporaryFailure == nil {
+			// without a definite answer to any probe catch-all is anyone's guess
+			for _, p := range probes {
+				if ret.TemporaryFailure = newTemporaryFailure(p.err); ret.TemporaryFailure != nil {
 					break
 				}
 			}
 		}
-		ret.CatchAll = isCatchAll
+		ret.CatchAllConfidence = catchAllConfidence(probes, real)
+		ret.CatchAll = isCatchAll(ret.CatchAllConfidence)
 		if ret.CatchAll {
-			return &ret, nil
+			// accepting the address means nothing on a catch-all domain
+			ret.Deliverable = false
 		}
+		v.checkMailboxCmds(client, username, email, &ret)
+		return &ret, nil
 	}
 
 	if username == "" {
 		return &ret, nil
 	}
 
-	if err = client.Rcpt(email); err == nil {
+	start := time.Now()
+	err = client.rcpt(email)
+	ret.timings.RCPTMs = since(start)
+	if err == nil {
 		ret.Deliverable = true
+	} else {
+		e := parseErrorAt(err, StageRCPT)
+		s.flagBlocked(e, false)
+		ret.TemporaryFailure = newTemporaryFailure(e)
 	}
+	v.checkMailboxCmds(client, username, email, &ret)
 
 	return &ret, nil
 }
 
-func newSMTPClient(domain, proxyURI, localAddr string, connectTimeout, operationTimeout time.Duration) (*smtp.Client, *net.MX, error) {
-	domain = domainToASCII(domain)
-	mxRecords, err := net.LookupMX(domain)
+// checkMailboxCmds sends the enabled VRFY and EXPN commands. They come last, as servers
+// treating them as harvesting may drop the connection.
+func (v *Verifier) checkMailboxCmds(client *smtpConn, username, email string, ret *SMTP) {
+	if username == "" {
+		return
+	}
+	if v.vrfyEnabled {
+		ret.VRFY = client.mailboxCmd("VRFY", email)
+	}
+	if v.expnEnabled {
+		ret.EXPN = client.mailboxCmd("EXPN", email)
+	}
+}
+
+// smtpSession is a connection that got past MAIL FROM and is ready for RCPT commands,
+// or an API verifier to ask instead when the MX is served by one.
+type smtpSession struct {
+	client   *smtpConn
+	api      *timedAPIVerifier
+	blocked  bool
+	reportIP func(blocked bool)
+}
+
+type timedAPIVerifier struct {
+	name     string
+	verifier smtpAPIVerifier
+	observe  func(Event)
+}
+
+func (a *timedAPIVerifier) check(domain, username string) (*SMTP, error) {
+	start := time.Now()
+	ret, err := a.verifier.check(domain, username)
+	a.observe(APICallEvent{API: a.name, Duration: time.Since(start), Err: err})
+	return ret, err
+}
+
+// flagBlocked remembers whether a reply hints at the source IP being blocked, to report
+// it to the IP pool when the session is closed.
+func (s *smtpSession) flagBlocked(e *LookupError, beforeRcpt bool) {
+	s.blocked = s.blocked || isIPBlockSignal(e, beforeRcpt)
+}
+
+func (s *smtpSession) close() {
+	if s.client != nil {
+		s.client.Close()
+	}
+	s.reportIP(s.blocked)
+}
+
+// startSMTP connects to the MX of domain and runs the commands up to MAIL FROM,
+// recording connection details in ret. The connection is closed when ctx is canceled.
+func (v *Verifier) startSMTP(ctx context.Context, domain string, ret *SMTP, d *domainState) (*smtpSession, error) {
+	start := time.Now()
+	mxRecords, err := d.mxRecords(ctx, v, domain)
+	ret.timings.DNSMs = since(start)
+	if err != nil {
+		return nil, parseErrorAt(err, StageDNS)
+	}
+	provider := detectProvider(mxRecords[0].Host)
+	ret.Provider = provider
+
+	proxyDialer, releaseProxy, err := v.acquireProxy(domain)
+	if err != nil {
+		return nil, parseErrorAt(err, StageConnect)
+	}
+	s := &smtpSession{reportIP: func(bool) {}}
+	var localIP net.IP
+	if proxyDialer != nil {
+		ret.Proxy = proxyDialer.redacted
+	} else {
+		if localIP, s.reportIP, err = v.acquireSourceIP(provider); err != nil {
+			return nil, parseErrorAt(err, StageConnect)
+		}
+		if localIP != nil {
+			ret.SourceIP = localIP.String()
+		}
+	}
+
+	fail := func(err error, stage string) (*smtpSession, error) {
+		if err == nil {
+			err = errors.New("unexpected reply")
+		}
+		e := parseErrorAt(err, stage)
+		s.flagBlocked(e, true)
+		s.close()
+		return nil, e
+	}
+
+	dialer := &smtpDialer{
+		ctx:              ctx,
+		proxy:            proxyDialer,
+		localIP:          localIP,
+		port:             v.smtpPort,
+		family:           v.addressFamily,
+		connectTimeout:   v.connectTimeout,
+		operationTimeout: v.operationTimeout,
+		observe:          v.observe,
+	}
+	start = time.Now()
+	s.client, err = newSMTPClient(mxRecords, dialer)
+	releaseProxy(err)
 	if err != nil {
-		return nil, nil, err
+		ret.timings.ConnectMs = since(start)
+		return fail(err, StageConnect)
+	}
+	ret.timings.addSMTP(&s.client.timings)
+	ret.RemoteIP = s.client.remoteIP.String()
+	ret.Server = s.client.server
+	if ret.Server.Provider != "" {
+		ret.Provider = ret.Server.Provider
+	}
+
+	for name, apiVerifier := range v.apiVerifiers {
+		if apiVerifier.isSupported(strings.ToLower(s.client.mx.Host)) {
+			s.api = &timedAPIVerifier{name: name, verifier: apiVerifier, observe: v.observe}
+			return s, nil
+		}
 	}
 
+	helloName := v.helloName
+	if proxyDialer == nil {
+		helloName = v.helloNameFor(s.client.localIP())
+	}
+	start = time.Now()
+	err = s.client.hello(helloName)
+	ret.timings.HELOMs = since(start)
+	if err != nil {
+		return fail(err, StageHELO)
+	}
+	if ret.Server.Provider != "" {
+		ret.Provider = ret.Server.Provider
+	}
+
+	if v.startTLSEnabled {
+		start = time.Now()
+		ret.TLS, err = v.negotiateTLS(s.client, domain)
+		ret.timings.TLSMs = since(start)
+		if err != nil && ret.TLS.required {
+			return fail(err, StageTLS)
+		}
+		if err != nil {
+			// a failed handshake leaves the connection unusable, so like a sender without
+			// a policy requiring TLS, go on in plain text on a new connection to the same MX
+			mx := s.client.mx
+			s.client.Close()
+			start = time.Now()
+			s.client, err = newSMTPClient([]*net.MX{mx}, dialer)
+			if err != nil {
+				ret.timings.ConnectMs += since(start)
+				return fail(err, StageConnect)
+			}
+			ret.timings.addSMTP(&s.client.timings)
+			ret.RemoteIP = s.client.remoteIP.String()
+			start = time.Now()
+			err = s.client.hello(helloName)
+			ret.timings.HELOMs += since(start)
+			if err != nil {
+				return fail(err, StageHELO)
+			}
+		}
+	}
+
+	start = time.Now()
+	err = s.client.mail(v.sender(domain))
+	ret.timings.MAILMs = since(start)
+	if err != nil {
+		return fail(err, StageMAIL)
+	}
+	return s, nil
+}
+
+func lookupMXRecords(ctx context.Context, domain string) ([]*net.MX, error) {
+	mxRecords, err := net.DefaultResolver.LookupMX(ctx, domainToASCII(domain))
+	if err != nil {
+		return nil, err
+	}
 	if len(mxRecords) == 0 {
-		return nil, nil, errors.New("No MX records found")
+		return nil, errNoMXRecords
 	}
-	ch := make(chan interface{}, 1)
-	selectedMXCh := make(chan *net.MX, 1)
+	return mxRecords, nil
+}
+
+func newSMTPClient(mxRecords []*net.MX, d *smtpDialer) (*smtpConn, error) {
+	ch := make(chan interface{}, len(mxRecords))
 
 	var done bool
 	var mutex sync.Mutex
 
-	for i, r := range mxRecords {
-		addr := r.Host + smtpPort
-		index := i
+	for _, r := range mxRecords {
+		mx := r
 		go func() {
-			c, err := dialSMTP(addr, proxyURI, localAddr, connectTimeout, operationTimeout)
+			c, err := dialSMTP(mx, d)
 			if err != nil {
-				if !done {
-					ch <- err
-				}
+				ch <- err
 				return
 			}
 
@@ -125,7 +414,6 @@ func newSMTPClient(domain, proxyURI, localAddr string, connectTimeout, operation
 			case !done:
 				done = true
 				ch <- c
-				selectedMXCh <- mxRecords[index]
 			default:
 				c.Close()
 			}
@@ -137,77 +425,106 @@ func newSMTPClient(domain, proxyURI, localAddr string, connectTimeout, operation
 	for {
 		res := <-ch
 		switch r := res.(type) {
-		case *smtp.Client:
-			return r, <-selectedMXCh, nil
+		case *smtpConn:
+			return r, nil
 		case error:
 			errs = append(errs, r)
 			if len(errs) == len(mxRecords) {
-				return nil, nil, errs[0]
+				return nil, errs[0]
 			}
 		default:
-			return nil, nil, errors.New("Unexpected response dialing SMTP server")
+			return nil, errors.New("Unexpected response dialing SMTP server")
 		}
 	}
 
 }
 
-func dialSMTP(addr, proxyURI, localAddr string, connectTimeout, operationTimeout time.Duration) (*smtp.Client, error) {
-	var conn net.Conn
-	var err error
+func dialSMTP(mx *net.MX, d *smtpDialer) (*smtpConn, error) {
+	ctx, cancel := context.WithTimeout(d.ctx, d.connectTimeout)
+	defer cancel()
+
+	start := time.Now()
+	ips, err := resolveMXHost(ctx, mx.Host, d.family, d.localIP)
+	d.observe(DNSLookupEvent{Type: "IP", Name: mx.Host, Duration: time.Since(start), Err: err})
+	timings := Timings{DNSMs: since(start)}
+	if err != nil {
+		return nil, err
+	}
 
-	if proxyURI != "" {
-		conn, err = establishProxyConnection(addr, proxyURI, connectTimeout)
+	var conn net.Conn
+	var remoteIP net.IP
+	start = time.Now()
+	port := strconv.Itoa(d.port)
+	if d.proxy != nil {
+		conn, remoteIP, err = dialAddresses(ctx, ips, port, 0, d.proxy.dial)
 	} else {
-		conn, err = establishConnection(addr, localAddr, connectTimeout)
+		stagger := time.Duration(0)
+		if d.family == FamilyHappyEyeballs || d.family == "" {
+			stagger = happyEyeballsDelay
+		}
+		conn, remoteIP, err = dialAddresses(ctx, ips, port, stagger, func(ctx context.Context, addr string) (net.Conn, error) {
+			return establishConnection(ctx, addr, d.localIP)
+		})
 	}
+	d.observe(newDialEvent(mx, remoteIP, d, time.Since(start), err))
+	timings.ConnectMs = since(start)
 	if err != nil {
 		return nil, err
 	}
 
-	err = conn.SetDeadline(time.Now().Add(operationTimeout))
+	err = conn.SetDeadline(time.Now().Add(d.operationTimeout))
 	if err != nil {
+		conn.Close()
 		return nil, err
 	}
-
-	host, _, _ := net.SplitHostPort(addr)
-	return smtp.NewClient(conn, host)
+	// canceling the verification aborts the session, however far it got
+	stop := context.AfterFunc(d.ctx, func() { conn.Close() })
+
+	rec := &recordingConn{Conn: conn}
+	rec.record()
+	start = time.Now()
+	client, err := smtp.NewClient(rec, strings.TrimSuffix(mx.Host, "."))
+	greetingDelay := time.Since(start)
+	timings.GreetingMs = greetingDelay.Milliseconds()
+	greeting := lastReplyLines(rec.stop())
+	if err != nil {
+		stop()
+		conn.Close()
+		return nil, err
+	}
+	return &smtpConn{
+		Client:   client,
+		mx:       mx,
+		remoteIP: remoteIP,
+		observe:  d.observe,
+		rec:      rec,
+		server:   newSMTPServer(greeting, greetingDelay),
+		timings:  timings,
+		stop:     stop,
+	}, nil
 }
 
+// GenerateSmartRandomEmails returns count distinct random addresses at domain with
+// realistic looking local parts.
 func GenerateSmartRandomEmails(domain string, count int) []string {
-	prefixes := []string{"verify.test.", "check.mail.", "nonexistent.", "test.user.", "audit.99."}
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 	emails := make([]string, 0, count)
+	seen := map[string]bool{}
 
-	for i := 0; i < count; i++ {
-		prefix := prefixes[rand.Intn(len(prefixes))]
-		suffix := fmt.Sprintf("%d", rand.Intn(10000))
-		emails = append(emails, fmt.Sprintf("%s%s@%s", prefix, suffix, domain))
+	for len(emails) < count {
+		email := fmt.Sprintf("%s@%s", randomLocalPart(r), domain)
+		if !seen[email] {
+			seen[email] = true
+			emails = append(emails, email)
+		}
 	}
 	return emails
 }
 
-func establishConnection(addr, localAddr string, timeout time.Duration) (net.Conn, error) {
-	d := net.Dialer{Timeout: timeout}
-	if localAddr != "" {
-		lAddr, err := net.ResolveTCPAddr("tcp", localAddr+":0")
-		if err == nil {
-			d.LocalAddr = lAddr
-		}
+func establishConnection(ctx context.Context, addr string, localIP net.IP) (net.Conn, error) {
+	var d net.Dialer
+	if localIP != nil {
+		d.LocalAddr = &net.TCPAddr{IP: localIP}
 	}
-	return d.Dial("tcp", addr)
-}
-
-func establishProxyConnection(addr, proxyURI string, timeout time.Duration) (net.Conn, error) {
-	u, err := url.Parse(proxyURI)
-	if err != nil {
-		return nil, err
-	}
-	dialer, err := proxy.FromURL(u, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-
-	return dialer.(proxy.ContextDialer).DialContext(ctx, "tcp", addr)
+	return d.DialContext(ctx, "tcp", addr)
 }
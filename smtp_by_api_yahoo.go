@@ -9,25 +9,22 @@ import (
 	"net/http"
 	"regexp"
 	"strings"
-	"time"
 )
 
 const (
 	signupPage     = "https://login.yahoo.com/account/create?specId=yidregsimplified&lang=en-US&src=&done=https%3A%2F%2Fwww.yahoo.com&display=login"
 	signupEndpoint = "https://login.yahoo.com/account/module/create?validateField=userId"
 	userAgent      = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/136.0.0.0 Safari/537.36"
-)
-func newYahooAPIVerifier(client *http.Client) smtpAPIVerifier {
-	if client == nil {
-		client = http.DefaultClient
-	}
+)
+
+func newYahooAPIVerifier(hc *httpConfig) smtpAPIVerifier {
 	return yahoo{
-		client: client,
+		http: hc,
 	}
 }
 
 type yahoo struct {
-	client *http.Client
+	http *httpConfig
 }
 
 type yahooValidateReq struct {
@@ -44,7 +41,7 @@ type errItem struct {
 	Error string `json:"error"`
 }
 
-func (y yahoo) isSupported(host string) bool {
+func (y yahoo) isSupported(host string) bool {
 	return strings.Contains(host, "yahoo")
 }
 
@@ -123,9 +120,9 @@ func (y yahoo) sendValidateRequest(req yahooValidateReq) (yahooErrorResp, error)
 	if err != nil {
 		return res, err
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := y.http.withTimeout(context.Background())
 	defer cancel()
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, signupEndpoint, bytes.NewReader(data))
+	request, err := y.http.newRequest(ctx, http.MethodPost, signupEndpoint, bytes.NewReader(data), userAgent)
 	if err != nil {
 		return res, err
 	}
@@ -134,7 +131,7 @@ func (y yahoo) sendValidateRequest(req yahooValidateReq) (yahooErrorResp, error)
 	}
 	request.Header.Add("X-Requested-With", "XMLHttpRequest")
 	request.Header.Add("Content-Type", "application/json; charset=UTF-8")
-	resp, err := y.client.Do(request)
+	resp, err := y.http.httpClient().Do(request)
 	if err != nil {
 		return res, err
 	}
@@ -147,14 +144,13 @@ func (y yahoo) sendValidateRequest(req yahooValidateReq) (yahooErrorResp, error)
 }
 
 func (y yahoo) toSignUpPage() ([]*http.Cookie, []byte, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := y.http.withTimeout(context.Background())
 	defer cancel()
-	request, err := http.NewRequestWithContext(ctx, http.MethodGet, signupPage, nil)
+	request, err := y.http.newRequest(ctx, http.MethodGet, signupPage, nil, userAgent)
 	if err != nil {
 		return nil, nil, err
 	}
-	request.Header.Add("User-Agent", userAgent)
-	resp, err := y.client.Do(request)
+	resp, err := y.http.httpClient().Do(request)
 	if err != nil {
 		return nil, nil, err
 	}
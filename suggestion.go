@@ -1,6 +1,8 @@
 package emailverifier
 
 import (
+	"iter"
+	"maps"
 	"strings"
 
 	"github.com/hbollon/go-edlib"
@@ -19,7 +21,7 @@ func (v *Verifier) SuggestDomain(domain string) string {
 
 	}
 
-	closestDomain := findClosestDomain(domain, freeDomains, domainThreshold)
+	closestDomain := findClosestDomain(domain, allFreeDomains, domainThreshold)
 	if closestDomain != "" {
 		if closestDomain == domain {
 			return ""
@@ -30,8 +32,8 @@ func (v *Verifier) SuggestDomain(domain string) string {
 	var localTypo bool
 	closestDomain = domain
 
-	closestSecondLevelDomain := findClosestDomain(sld, suggestionSecondLevelDomains, secondLevelThreshold)
-	closestTopLevelDomain := findClosestDomain(tld, suggestionTopLevelDomains, topLevelThreshold)
+	closestSecondLevelDomain := findClosestDomain(sld, maps.Keys(suggestionSecondLevelDomains), secondLevelThreshold)
+	closestTopLevelDomain := findClosestDomain(tld, maps.Keys(suggestionTopLevelDomains), topLevelThreshold)
 
 	if closestSecondLevelDomain != "" && closestSecondLevelDomain != sld {
 		localTypo = true
@@ -49,11 +51,11 @@ func (v *Verifier) SuggestDomain(domain string) string {
 
 	return ""
 }
-func findClosestDomain(domain string, domains map[string]bool, threshold float32) string {
+func findClosestDomain(domain string, domains iter.Seq[string], threshold float32) string {
 	var maxDist = float32(-1)
 	var closestDomain string
 
-	if domain == "" || len(domains) == 0 {
+	if domain == "" {
 		return closestDomain
 	}
 
@@ -63,7 +65,8 @@ func findClosestDomain(domain string, domains map[string]bool, threshold float32
 		}
 
 		dist, _ := edlib.StringsSimilarity(domain, d, edlib.Levenshtein)
-		if dist > maxDist {
+		// ties go to the smallest name, the domains may come in any order
+		if dist > maxDist || (dist == maxDist && d < closestDomain) {
 			maxDist = dist
 			closestDomain = d
 		}
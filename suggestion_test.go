@@ -0,0 +1,21 @@
+package emailverifier
+
+import "testing"
+
+func TestSuggestDomainRuntimeFreeDomains(t *testing.T) {
+	v := NewVerifier()
+	if got := v.SuggestDomain("gmai.com"); got != "gmail.com" {
+		t.Fatalf("SuggestDomain(gmai.com) = %q, want gmail.com", got)
+	}
+
+	v.AddFreeDomains([]string{"examplemail.test"})
+	defer v.RemoveFreeDomains([]string{"examplemail.test"})
+	if got := v.SuggestDomain("examplemal.test"); got != "examplemail.test" {
+		t.Errorf("SuggestDomain(examplemal.test) = %q, want examplemail.test", got)
+	}
+
+	v.RemoveFreeDomains([]string{"examplemail.test"})
+	if got := v.SuggestDomain("examplemal.test"); got == "examplemail.test" {
+		t.Errorf("SuggestDomain(examplemal.test) = %q after removing the domain", got)
+	}
+}
@@ -0,0 +1,125 @@
+package emailverifier
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCodeGreylisted is the TemporaryFailure reason for servers deferring unknown senders
+// on purpose. A LookupError never carries it.
+const ErrCodeGreylisted ErrorCode = "greylisted"
+
+const (
+	minRetryAfter         = 30 * time.Second
+	maxRetryAfter         = 24 * time.Hour
+	greylistRetryAfter    = 5 * time.Minute
+	defaultRetryAfter     = 15 * time.Minute
+	rateLimitedRetryAfter = time.Hour
+)
+
+var (
+	greylistRegex   = regexp.MustCompile(`(?i)gr[ae]y-?list`)
+	retryAfterRegex = regexp.MustCompile(`(?i)\b(?:in|after|for|wait)\s+(\d+)\s*(seconds?|secs?|s|minutes?|mins?|m|hours?|h)\b`)
+)
+
+// TemporaryFailure explains why the server deferred its answer, e.g. greylisting or
+// rate limiting. Reachable stays unknown, verifying again after RetryAfter seconds is
+// likely to settle it.
+type TemporaryFailure struct {
+	Reason       ErrorCode `json:"reason"`
+	Stage        string    `json:"stage"`
+	SMTPCode     int       `json:"smtp_code,omitempty"`
+	EnhancedCode string    `json:"enhanced_code,omitempty"`
+	Message      string    `json:"message"`
+	RetryAfter   int64     `json:"retry_after"`
+}
+
+// newTemporaryFailure returns nil when e is not worth retrying.
+func newTemporaryFailure(e *LookupError) *TemporaryFailure {
+	if e == nil || !e.Retryable {
+		return nil
+	}
+	t := &TemporaryFailure{
+		Reason:       e.Code,
+		Stage:        e.Stage,
+		SMTPCode:     e.SMTPCode,
+		EnhancedCode: e.EnhancedCode,
+		Message:      e.Details,
+	}
+	if greylistRegex.MatchString(e.Details) {
+		t.Reason = ErrCodeGreylisted
+	}
+	t.RetryAfter = int64(retryAfter(t.Reason, e.Details).Seconds())
+	return t
+}
+
+// retryAfter takes the delay from the reply text when the server names one, e.g.
+// "try again in 5 minutes", and guesses it from the reason otherwise.
+func retryAfter(reason ErrorCode, details string) time.Duration {
+	if m := retryAfterRegex.FindStringSubmatch(details); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := time.Second
+		switch strings.ToLower(m[2])[0] {
+		case 'm':
+			unit = time.Minute
+		case 'h':
+			unit = time.Hour
+		}
+		return min(max(time.Duration(n)*unit, minRetryAfter), maxRetryAfter)
+	}
+	switch reason {
+	case ErrCodeGreylisted:
+		return greylistRetryAfter
+	case ErrCodeTooManyRCPT, ErrCodeExceededMessagingLimits, ErrCodeBlocked:
+		return rateLimitedRetryAfter
+	}
+	return defaultRetryAfter
+}
+
+// ScheduleRetry verifies r.Email again once r's retry-after has passed, and again each
+// time the server keeps deferring, up to maxAttempts more verifications. done receives
+// the last result. ok is false, and nothing is scheduled, when r did not fail temporarily.
+func (v *Verifier) ScheduleRetry(r *Result, maxAttempts int, done func(*Result)) (cancel func(), ok bool) {
+	if r == nil || r.TemporaryFailure == nil || maxAttempts < 1 {
+		return func() {}, false
+	}
+
+	var mu sync.Mutex
+	var timer *time.Timer
+	canceled := false
+	attempts := 0
+
+	var schedule func(prev *Result)
+	schedule = func(prev *Result) {
+		mu.Lock()
+		defer mu.Unlock()
+		if canceled {
+			return
+		}
+		timer = time.AfterFunc(time.Duration(prev.TemporaryFailure.RetryAfter)*time.Second, func() {
+			res, _ := v.Verify(prev.Email)
+			attempts++
+			if res.TemporaryFailure != nil && attempts < maxAttempts {
+				schedule(res)
+				return
+			}
+			mu.Lock()
+			stopped := canceled
+			mu.Unlock()
+			if !stopped {
+				done(res)
+			}
+		})
+	}
+	schedule(r)
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		canceled = true
+		timer.Stop()
+	}, true
+}
@@ -0,0 +1,46 @@
+package emailverifier
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		reason  ErrorCode
+		details string
+		want    time.Duration
+	}{
+		{ErrCodeMailboxBusy, "450 4.2.1 Mailbox busy, try again in 5 minutes", 5 * time.Minute},
+		{ErrCodeTryAgainLater, "421 4.7.0 Please retry after 2h", 2 * time.Hour},
+		{ErrCodeMailboxBusy, "450 4.7.1 Wait 600 seconds", 10 * time.Minute},
+		{ErrCodeMailboxBusy, "450 4.7.1 Try again in 5 secs", minRetryAfter},
+		{ErrCodeTryAgainLater, "421 4.7.0 Blocked for 72 hours", maxRetryAfter},
+		{ErrCodeGreylisted, "451 4.7.1 Greylisted", greylistRetryAfter},
+		{ErrCodeTooManyRCPT, "452 4.5.3 Too many recipients", rateLimitedRetryAfter},
+		{ErrCodeExceededMessagingLimits, "451 4.7.1 Rate limited", rateLimitedRetryAfter},
+		{ErrCodeTimeout, "dial tcp 192.0.2.1:25: i/o timeout", defaultRetryAfter},
+		{ErrCodeMailboxBusy, "450 4.2.1 Mailbox busy in 2025", defaultRetryAfter},
+	}
+	for _, tt := range tests {
+		if got := retryAfter(tt.reason, tt.details); got != tt.want {
+			t.Errorf("retryAfter(%s, %q) = %v, want %v", tt.reason, tt.details, got, tt.want)
+		}
+	}
+}
+
+func TestNewTemporaryFailure(t *testing.T) {
+	e := newLookupError(ErrMailboxBusy, "450 4.2.0 Greylisted, please try again in 300 seconds")
+	e.Stage = StageRCPT
+	f := newTemporaryFailure(e)
+	if f == nil {
+		t.Fatal("newTemporaryFailure(450) = nil")
+	}
+	if f.Reason != ErrCodeGreylisted || f.Stage != StageRCPT || f.SMTPCode != 450 || f.EnhancedCode != "4.2.0" || f.RetryAfter != 300 {
+		t.Errorf("newTemporaryFailure(450) = %+v", f)
+	}
+
+	if f := newTemporaryFailure(newLookupError(ErrServerUnavailable, "550 5.1.1 User unknown")); f != nil {
+		t.Errorf("newTemporaryFailure(550) = %+v, want nil", f)
+	}
+}
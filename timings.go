@@ -0,0 +1,54 @@
+package emailverifier
+
+import "time"
+
+// Timings breaks the time spent verifying an address down by stage, in milliseconds.
+// DNS adds up the MX and MX host lookups. Connect, greeting, HELO, TLS and MAIL belong
+// to the connection that won the race between MX hosts.
+type Timings struct {
+	DNSMs      int64 `json:"dns_ms"`
+	ConnectMs  int64 `json:"connect_ms"`
+	GreetingMs int64 `json:"greeting_ms"`
+	HELOMs     int64 `json:"helo_ms"`
+	TLSMs      int64 `json:"tls_ms"`
+	MAILMs     int64 `json:"mail_ms"`
+	RCPTMs     int64 `json:"rcpt_ms"`
+	CatchAllMs int64 `json:"catch_all_ms"`
+	APIMs      int64 `json:"api_ms"`
+	GravatarMs int64 `json:"gravatar_ms"`
+	TotalMs    int64 `json:"total_ms"`
+}
+
+// stages lists the stage timings by name, leaving out the total.
+func (t *Timings) stages() map[string]int64 {
+	return map[string]int64{
+		"dns":       t.DNSMs,
+		"connect":   t.ConnectMs,
+		"greeting":  t.GreetingMs,
+		"helo":      t.HELOMs,
+		"tls":       t.TLSMs,
+		"mail":      t.MAILMs,
+		"rcpt":      t.RCPTMs,
+		"catch_all": t.CatchAllMs,
+		"api":       t.APIMs,
+		"gravatar":  t.GravatarMs,
+	}
+}
+
+// addSMTP adds the stages measured by CheckSMTP.
+func (t *Timings) addSMTP(s *Timings) {
+	t.DNSMs += s.DNSMs
+	t.ConnectMs += s.ConnectMs
+	t.GreetingMs += s.GreetingMs
+	t.HELOMs += s.HELOMs
+	t.TLSMs += s.TLSMs
+	t.MAILMs += s.MAILMs
+	t.RCPTMs += s.RCPTMs
+	t.CatchAllMs += s.CatchAllMs
+	t.APIMs += s.APIMs
+}
+
+// since returns the milliseconds elapsed since start.
+func since(start time.Time) int64 {
+	return time.Since(start).Milliseconds()
+}
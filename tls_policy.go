@@ -0,0 +1,180 @@
+package emailverifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DANEValid           = "valid"
+	DANEMismatch        = "mismatch"
+	DANEUnauthenticated = "unauthenticated"
+
+	mtaSTSDefaultCacheTTL = time.Hour
+	mtaSTSMaxCacheTTL     = 24 * time.Hour
+)
+
+// SMTPTLS describes the STARTTLS session with the MX host and how it measures up to
+// the transport security policies the domain publishes.
+type SMTPTLS struct {
+	STARTTLS         bool     `json:"starttls"`
+	Version          string   `json:"version,omitempty"`
+	CipherSuite      string   `json:"cipher_suite,omitempty"`
+	CertificateValid bool     `json:"certificate_valid"`
+	CertificateError string   `json:"certificate_error,omitempty"`
+	DANE             string   `json:"dane,omitempty"`
+	MTASTS           string   `json:"mta_sts,omitempty"`
+	Mismatches       []string `json:"mismatches"`
+	// Error is set when STARTTLS failed and verification went on without TLS.
+	Error string `json:"error,omitempty"`
+
+	// required is set when DANE or an enforced MTA-STS policy applies to the host
+	required bool
+}
+
+type mtaSTSCacheEntry struct {
+	policy  *MTASTS
+	expires time.Time
+}
+
+var mtaSTSSyncCache sync.Map
+
+// EnableSTARTTLS upgrades SMTP connections with STARTTLS when offered, and checks the
+// session against the DANE TLSA records of the MX host and the MTA-STS policy of the
+// domain.
+func (v *Verifier) EnableSTARTTLS() *Verifier {
+	v.startTLSEnabled = true
+	return v
+}
+
+func (v *Verifier) DisableSTARTTLS() *Verifier {
+	v.startTLSEnabled = false
+	return v
+}
+
+func (v *Verifier) negotiateTLS(client *smtpConn, domain string) (*SMTPTLS, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), v.operationTimeout)
+	defer cancel()
+
+	host := strings.TrimSuffix(client.mx.Host, ".")
+	ret := &SMTPTLS{Mismatches: []string{}}
+
+	var policy *MTASTS
+	var tlsa []tlsaRecord
+	var authenticated bool
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		policy = v.mtaSTSPolicy(ctx, domainToASCII(domain))
+	}()
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		var err error
+		tlsa, authenticated, err = lookupTLSA(ctx, host, v.smtpPort)
+		v.observe(DNSLookupEvent{Type: "TLSA", Name: host, Duration: time.Since(start), Err: err})
+	}()
+	wg.Wait()
+
+	stsEnforced := policy != nil && policy.Error == "" && policy.Mode != MTASTSModeNone
+	if policy != nil {
+		ret.MTASTS = policy.Mode
+	}
+	if stsEnforced && !policy.matches(host) {
+		ret.Mismatches = append(ret.Mismatches, fmt.Sprintf("mx host %s is not listed in the mta-sts policy", host))
+	}
+	daneEnforced := authenticated && len(tlsa) > 0
+	if len(tlsa) > 0 && !authenticated {
+		ret.DANE = DANEUnauthenticated
+	}
+
+	if ok, _ := client.Extension("STARTTLS"); !ok {
+		if stsEnforced {
+			ret.Mismatches = append(ret.Mismatches, "STARTTLS is not offered but the mta-sts policy requires it")
+		}
+		if daneEnforced {
+			ret.DANE = DANEMismatch
+			ret.Mismatches = append(ret.Mismatches, "STARTTLS is not offered but tlsa records are published")
+		}
+		return ret, nil
+	}
+
+	// certificates are verified below, against the policies that apply
+	err := client.cmd("STARTTLS", func() error {
+		return client.StartTLS(&tls.Config{ServerName: host, InsecureSkipVerify: true})
+	})
+	if err != nil {
+		ret.Error = err.Error()
+		ret.required = stsEnforced || daneEnforced
+		if ret.required {
+			ret.Mismatches = append(ret.Mismatches, "STARTTLS failed but the domain requires TLS")
+		}
+		return ret, err
+	}
+	ret.STARTTLS = true
+	state, _ := client.TLSConnectionState()
+	ret.Version = tls.VersionName(state.Version)
+	ret.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+
+	if err = verifyCertificate(host, state.PeerCertificates); err != nil {
+		ret.CertificateError = err.Error()
+		if stsEnforced {
+			ret.Mismatches = append(ret.Mismatches, "certificate is not valid but the mta-sts policy requires it")
+		}
+	} else {
+		ret.CertificateValid = true
+	}
+
+	if daneEnforced {
+		if matchTLSA(tlsa, state.PeerCertificates, ret.CertificateValid) {
+			ret.DANE = DANEValid
+		} else {
+			ret.DANE = DANEMismatch
+			ret.Mismatches = append(ret.Mismatches, "no tlsa record matches the presented certificate")
+		}
+	}
+	return ret, nil
+}
+
+func verifyCertificate(host string, chain []*x509.Certificate) error {
+	if len(chain) == 0 {
+		return errors.New("no certificate presented")
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{DNSName: host, Intermediates: intermediates})
+	return err
+}
+
+// mtaSTSPolicy caches policies for their max_age, at most a day. Domains without a
+// policy are cached too, policies that failed to fetch are not.
+func (v *Verifier) mtaSTSPolicy(ctx context.Context, domain string) *MTASTS {
+	if cached, ok := mtaSTSSyncCache.Load(domain); ok {
+		entry := cached.(mtaSTSCacheEntry)
+		if time.Now().Before(entry.expires) {
+			v.observe(CacheEvent{Cache: CacheMTASTS, Hit: true})
+			return entry.policy
+		}
+	}
+	v.observe(CacheEvent{Cache: CacheMTASTS})
+
+	policy, _ := lookupMTASTS(ctx, domain, &v.http)
+	if policy != nil && policy.Error != "" {
+		return policy
+	}
+	ttl := mtaSTSDefaultCacheTTL
+	if policy != nil && policy.MaxAge > 0 {
+		ttl = min(time.Duration(policy.MaxAge)*time.Second, mtaSTSMaxCacheTTL)
+	}
+	mtaSTSSyncCache.Store(domain, mtaSTSCacheEntry{policy: policy, expires: time.Now().Add(ttl)})
+	return policy
+}
@@ -2,6 +2,7 @@ package emailverifier
 
 import (
 	"crypto/md5"
+	"crypto/sha256"
 	"encoding/hex"
 	"reflect"
 	"strings"
@@ -51,3 +52,8 @@ func getMD5Hash(str string) (error, string) {
 	}
 	return nil, hex.EncodeToString(h.Sum(nil))
 }
+
+func getSHA256Hash(str string) string {
+	sum := sha256.Sum256([]byte(str))
+	return hex.EncodeToString(sum[:])
+}
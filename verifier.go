@@ -1,8 +1,8 @@
 package emailverifier
 
 import (
+	"context"
 	"fmt"
-	"net/http"
 	"time"
 )
 
@@ -11,11 +11,28 @@ type Verifier struct {
 	catchAllCheckEnabled bool
 	domainSuggestEnabled bool
 	gravatarCheckEnabled bool
+	domainHealthEnabled  bool
+	nameInferenceEnabled bool
+	startTLSEnabled      bool
+	vrfyEnabled          bool
+	expnEnabled          bool
+	strictErrors         bool
+	autoHelloName        bool
+	catchAllProbes       int
+	smtpPort             int
 	fromEmail            string
+	senderOverrides      map[string]string
 	helloName            string
 	schedule             *schedule
 	proxyURI             string
+	proxyPool            *ProxyPool
+	ipPool               *IPPool
+	addressFamily        string
 	apiVerifiers         map[string]smtpAPIVerifier
+	gravatar             gravatarConfig
+	observer             Observer
+	dkimSelectors        []string
+	http                 httpConfig
 
 	connectTimeout   time.Duration
 	operationTimeout time.Duration
@@ -23,38 +40,83 @@ type Verifier struct {
 }
 
 type Result struct {
-	Email        string    `json:"email"`
-	Reachable    string    `json:"reachable"`
-	Syntax       Syntax    `json:"syntax"`
-	SMTP         *SMTP     `json:"smtp"`
-	Gravatar     *Gravatar `json:"gravatar"`
-	Suggestion   string    `json:"suggestion"`
-	Disposable   bool      `json:"disposable"`
-	RoleAccount  bool      `json:"role_account"`
-	Free         bool      `json:"free"`
-	HasMxRecords bool      `json:"has_mx_records"`
-}
-
-var additionalDisposableDomains map[string]bool = map[string]bool{}
-
-func init() {
-	for d := range disposableDomains {
-		disposableSyncDomains.Store(d, struct{}{})
+	Email        string               `json:"email"`
+	Reachable    string               `json:"reachable"`
+	Syntax       Syntax               `json:"syntax"`
+	SMTP         *SMTP                `json:"smtp"`
+	Gravatar     *Gravatar            `json:"gravatar"`
+	Suggestion   string               `json:"suggestion"`
+	Disposable   bool                 `json:"disposable"`
+	RoleAccount  bool                 `json:"role_account"`
+	RoleCategory string               `json:"role_category"`
+	Free         bool                 `json:"free"`
+	FreeProvider *FreeProvider        `json:"free_provider"`
+	HasMxRecords bool                 `json:"has_mx_records"`
+	DomainHealth *DomainHealthSummary `json:"domain_health"`
+	Failure      *Failure             `json:"failure"`
+	Timings      Timings              `json:"timings"`
+
+	// TemporaryFailure tells why Reachable is unknown when the server, or the way to
+	// it, failed in a way that may pass.
+	TemporaryFailure *TemporaryFailure `json:"temporary_failure"`
+
+	PossibleSpamTrap bool          `json:"possible_spam_trap"`
+	Gibberish        bool          `json:"gibberish"`
+	Risk             *Risk         `json:"risk"`
+	Name             *InferredName `json:"name"`
+}
+
+// Failure tells which stage of a verification failed. The rest of the Result holds what
+// could be determined anyway.
+type Failure struct {
+	Stage     string    `json:"stage"`
+	Reason    ErrorCode `json:"reason"`
+	Message   string    `json:"message"`
+	Retryable bool      `json:"retryable"`
+}
+
+func newFailure(e *LookupError) *Failure {
+	if e == nil {
+		return nil
 	}
+	return &Failure{Stage: e.Stage, Reason: e.Code, Message: e.Message, Retryable: e.Retryable}
 }
 
 func NewVerifier() *Verifier {
 	return &Verifier{
 		fromEmail:            defaultFromEmail,
+		senderOverrides:      map[string]string{},
 		helloName:            defaultHelloName,
+		smtpPort:             defaultSMTPPort,
 		catchAllCheckEnabled: true,
+		catchAllProbes:       defaultCatchAllProbes,
 		apiVerifiers:         map[string]smtpAPIVerifier{},
+		gravatar:             defaultGravatarConfig(),
 		connectTimeout:       10 * time.Second,
 		operationTimeout:     10 * time.Second,
+		addressFamily:        FamilyHappyEyeballs,
 	}
 }
 
 func (v *Verifier) Verify(email string) (*Result, error) {
+	// a state of its own lets the SMTP check reuse the MX lookup
+	return v.verifyWith(context.Background(), email, &domainState{})
+}
+
+// verifyWith verifies email reusing what d already knows about its domain. Canceling
+// ctx aborts the SMTP session.
+func (v *Verifier) verifyWith(ctx context.Context, email string, d *domainState) (*Result, error) {
+	start := time.Now()
+	ret, err := v.verify(ctx, email, d)
+	ret.Timings.TotalMs = since(start)
+	v.observe(ResultEvent{Email: email, Result: ret, Duration: time.Since(start), Err: err})
+	if !v.strictErrors {
+		return ret, nil
+	}
+	return ret, err
+}
+
+func (v *Verifier) verify(ctx context.Context, email string, d *domainState) (*Result, error) {
 
 	ret := Result{
 		Email:     email,
@@ -67,8 +129,20 @@ func (v *Verifier) Verify(email string) (*Result, error) {
 		return &ret, nil
 	}
 
-	ret.Free = v.IsFreeDomain(syntax.Domain)
-	ret.RoleAccount = v.IsRoleAccount(syntax.Username)
+	if provider := v.FreeProvider(syntax.Domain); provider != nil {
+		ret.Free = true
+		if *provider != (FreeProvider{}) {
+			ret.FreeProvider = provider
+		}
+	}
+	ret.RoleCategory = v.RoleCategory(syntax.Username)
+	ret.RoleAccount = ret.RoleCategory != ""
+	ret.Risk = v.AssessRisk(syntax.Username, syntax.Domain)
+	ret.PossibleSpamTrap = ret.Risk.PossibleSpamTrap()
+	ret.Gibberish = ret.Risk.Gibberish()
+	if v.nameInferenceEnabled && !ret.RoleAccount {
+		ret.Name = v.InferName(syntax.Username)
+	}
 	ret.Disposable = v.IsDisposable(syntax.Domain)
 	if v.domainSuggestEnabled {
 		ret.Suggestion = v.SuggestDomain(syntax.Domain)
@@ -78,38 +152,71 @@ func (v *Verifier) Verify(email string) (*Result, error) {
 		return &ret, nil
 	}
 
-	mx, err := v.CheckMX(syntax.Domain)
-	if err != nil {
-		errStr := err.Error()
-		if insContains(errStr, "no such host") {
-			ret.Reachable = reachableNo
-			return &ret, newLookupError(ErrNoSuchHost, errStr)
+	// the first failure is reported, the remaining checks still run
+	var failure *LookupError
+	fail := func(e *LookupError) {
+		if failure == nil && e != nil {
+			failure = e
+			ret.Failure = newFailure(e)
 		}
-		return &ret, err
 	}
-	ret.HasMxRecords = mx.HasMXRecord
 
-	smtp, err := v.CheckSMTP(syntax.Domain, syntax.Username)
+	start := time.Now()
+	mx, err := d.checkMX(v, syntax.Domain)
+	ret.Timings.DNSMs = since(start)
 	if err != nil {
-		return &ret, err
+		e := parseErrorAt(err, StageDNS)
+		if e.Code == ErrCodeNoSuchHost {
+			ret.Reachable = reachableNo
+		}
+		ret.TemporaryFailure = newTemporaryFailure(e)
+		fail(e)
+	} else {
+		ret.HasMxRecords = mx.HasMXRecord
+
+		if v.domainHealthEnabled {
+			if health, err := d.checkDomainHealth(v, syntax.Domain); err != nil {
+				fail(parseErrorAt(err, StageDNS))
+			} else {
+				ret.DomainHealth = health.Summary()
+			}
+		}
+
+		smtp, err := v.checkSMTP(ctx, syntax.Domain, syntax.Username, d)
+		ret.SMTP = smtp
+		if smtp != nil {
+			ret.Timings.addSMTP(&smtp.timings)
+		}
+		if err != nil {
+			e := parseErrorAt(err, StageConnect)
+			ret.TemporaryFailure = newTemporaryFailure(e)
+			fail(e)
+		} else if smtp != nil {
+			// nil when SMTP checks are disabled
+			ret.TemporaryFailure = smtp.TemporaryFailure
+			ret.Reachable = v.calculateReachable(smtp)
+		}
 	}
-	ret.SMTP = smtp
-	ret.Reachable = v.calculateReachable(smtp)
 
 	if v.gravatarCheckEnabled {
+		start := time.Now()
 		gravatar, err := v.CheckGravatar(email)
+		ret.Timings.GravatarMs = since(start)
 		if err != nil {
-			return &ret, err
+			fail(parseErrorAt(err, StageGravatar))
+		} else {
+			ret.Gravatar = gravatar
 		}
-		ret.Gravatar = gravatar
 	}
 
+	if failure != nil {
+		return &ret, failure
+	}
 	return &ret, nil
 }
 
 func (v *Verifier) AddDisposableDomains(domains []string) *Verifier {
 	for _, d := range domains {
-		additionalDisposableDomains[d] = true
 		disposableSyncDomains.Store(d, struct{}{})
 	}
 	return v
@@ -125,6 +232,18 @@ func (v *Verifier) DisableGravatarCheck() *Verifier {
 	return v
 }
 
+// EnableStrictErrors makes Verify return the failure as an error besides reporting it in
+// Result.Failure.
+func (v *Verifier) EnableStrictErrors() *Verifier {
+	v.strictErrors = true
+	return v
+}
+
+func (v *Verifier) DisableStrictErrors() *Verifier {
+	v.strictErrors = false
+	return v
+}
+
 func (v *Verifier) EnableSMTPCheck() *Verifier {
 	v.smtpCheckEnabled = true
 	return v
@@ -133,7 +252,7 @@ func (v *Verifier) EnableSMTPCheck() *Verifier {
 func (v *Verifier) EnableAPIVerifier(name string) error {
 	switch name {
 	case YAHOO:
-		v.apiVerifiers[YAHOO] = newYahooAPIVerifier(http.DefaultClient)
+		v.apiVerifiers[YAHOO] = newYahooAPIVerifier(&v.http)
 	default:
 		return fmt.Errorf("unsupported to enable the API verifier for vendor: %s", name)
 	}
@@ -171,8 +290,8 @@ func (v *Verifier) DisableDomainSuggest() *Verifier {
 
 func (v *Verifier) EnableAutoUpdateDisposable() *Verifier {
 	v.stopCurrentSchedule()
-	_ = updateDisposableDomains(disposableDataURL)
-	v.schedule = newSchedule(24*time.Hour, updateDisposableDomains, disposableDataURL)
+	_ = updateDisposableDomains(disposableDataURL, &v.http)
+	v.schedule = newSchedule(24*time.Hour, updateDisposableDomains, disposableDataURL, &v.http)
 	v.schedule.start()
 	return v
 }
@@ -183,6 +302,7 @@ func (v *Verifier) DisableAutoUpdateDisposable() *Verifier {
 
 }
 
+// FromEmail sets the MAIL FROM address. NullSender sends the null reverse-path.
 func (v *Verifier) FromEmail(email string) *Verifier {
 	v.fromEmail = email
 	return v
@@ -220,7 +340,7 @@ func (v *Verifier) calculateReachable(s *SMTP) string {
 	if s.Deliverable {
 		return reachableYes
 	}
-	if s.CatchAll {
+	if s.CatchAll || s.TemporaryFailure != nil {
 		return reachableUnknown
 	}
 	return reachableNo
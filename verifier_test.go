@@ -0,0 +1,76 @@
+package emailverifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// knownMX returns a domainState that already looked up the MX of its domain.
+func knownMX(host string) *domainState {
+	d := &domainState{}
+	d.mxOnce.Do(func() {
+		d.mx = &Mx{HasMXRecord: true, Records: []*net.MX{{Host: host, Pref: 10}}}
+	})
+	return d
+}
+
+// serveSMTP runs an SMTP server on 127.0.0.1 accepting every command, and returns its
+// port.
+func serveSMTP(t *testing.T) int {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				r := bufio.NewReader(conn)
+				conn.Write([]byte("220 mx.example.com ESMTP\r\n"))
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if strings.HasPrefix(strings.ToUpper(line), "QUIT") {
+						conn.Write([]byte("221 Bye\r\n"))
+						return
+					}
+					conn.Write([]byte("250 Ok\r\n"))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().(*net.TCPAddr).Port
+}
+
+func TestVerifySMTPDisabled(t *testing.T) {
+	v := NewVerifier()
+	ret, err := v.verifyWith(context.Background(), "john@example.com", knownMX("mx.example.com."))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !ret.HasMxRecords || ret.SMTP != nil || ret.Reachable != reachableUnknown || ret.Failure != nil {
+		t.Errorf("Verify = %+v, want MX records, no SMTP result and reachable unknown", ret)
+	}
+}
+
+func TestVerifyUsesBatchMX(t *testing.T) {
+	// the MX only exists in the domain state, a fresh lookup would fail
+	v := NewVerifier().EnableSMTPCheck().SMTPPort(serveSMTP(t))
+	ret, err := v.verifyWith(context.Background(), "john@mx-cache.invalid", knownMX("127.0.0.1"))
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if ret.SMTP == nil || !ret.SMTP.HostExists || ret.SMTP.RemoteIP != "127.0.0.1" {
+		t.Errorf("Verify = %+v, failure %+v, want an SMTP session with 127.0.0.1", ret.SMTP, ret.Failure)
+	}
+}
@@ -0,0 +1,99 @@
+package emailverifier
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	VRFYExists       = "exists"
+	VRFYNotFound     = "not_found"
+	VRFYCannotVerify = "cannot_verify"
+	VRFYUnsupported  = "unsupported"
+	VRFYRefused      = "refused"
+	VRFYTemporary    = "temporary"
+	VRFYError        = "error"
+)
+
+var vrfyNotFoundRegex = regexp.MustCompile(`(?i)\b5\.1\.\d{1,3}\b|unknown|no such|not found|does not exist|unrouteable|invalid (user|mailbox|recipient)`)
+
+// VRFYResult is the server's answer to VRFY or EXPN. It is reported next to the RCPT
+// based verdict and never changes it.
+type VRFYResult struct {
+	Command    string   `json:"command"`
+	Advertised bool     `json:"advertised"`
+	Result     string   `json:"result"`
+	Code       int      `json:"code"`
+	Lines      []string `json:"lines"`
+	Error      string   `json:"error,omitempty"`
+}
+
+// EnableVRFY asks the server with VRFY whether the mailbox exists, even when it does
+// not advertise the command. Servers still answering it are rare but authoritative,
+// also on catch-all domains.
+func (v *Verifier) EnableVRFY() *Verifier {
+	v.vrfyEnabled = true
+	return v
+}
+
+func (v *Verifier) DisableVRFY() *Verifier {
+	v.vrfyEnabled = false
+	return v
+}
+
+// EnableEXPN also sends EXPN, which lists the members when the address is a mailing list.
+func (v *Verifier) EnableEXPN() *Verifier {
+	v.expnEnabled = true
+	return v
+}
+
+func (v *Verifier) DisableEXPN() *Verifier {
+	v.expnEnabled = false
+	return v
+}
+
+// mailboxCmd sends VRFY or EXPN for addr. Any 2xx reply is a success.
+func (c *smtpConn) mailboxCmd(verb, addr string) *VRFYResult {
+	ret := &VRFYResult{Command: verb, Lines: []string{}}
+	for _, ext := range c.server.Capabilities {
+		if strings.EqualFold(ext, verb) {
+			ret.Advertised = true
+		}
+	}
+
+	var msg string
+	err := c.cmd(verb, func() error {
+		id, err := c.Text.Cmd("%s %s", verb, addr)
+		if err != nil {
+			return err
+		}
+		c.Text.StartResponse(id)
+		defer c.Text.EndResponse(id)
+		ret.Code, msg, err = c.Text.ReadResponse(2)
+		return err
+	})
+	if msg != "" {
+		ret.Lines = strings.Split(msg, "\n")
+	}
+
+	switch {
+	case ret.Code == 0:
+		ret.Result = VRFYError
+		if err != nil {
+			ret.Error = err.Error()
+		}
+	case ret.Code == 252:
+		ret.Result = VRFYCannotVerify
+	case ret.Code < 300:
+		ret.Result = VRFYExists
+	case ret.Code == 500 || ret.Code == 502 || ret.Code == 504:
+		ret.Result = VRFYUnsupported
+	case ret.Code < 500:
+		ret.Result = VRFYTemporary
+	case (ret.Code == 550 || ret.Code == 551 || ret.Code == 553) && vrfyNotFoundRegex.MatchString(msg):
+		ret.Result = VRFYNotFound
+	default:
+		ret.Result = VRFYRefused
+	}
+	return ret
+}